@@ -0,0 +1,210 @@
+package eep
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/mknyszek/eep/ease"
+)
+
+// tickDelta is the time assumed to pass between ticks for a Slide
+// driven only through the plain Slide.Update method, which has no way
+// to receive the real frame delta. It mirrors Slide.Update's own
+// documented assumption of a 60Hz tick rate.
+const tickDelta = time.Second / 60
+
+// AnimatedSlide is a Slide whose update logic wants the actual elapsed
+// time since the previous tick, for example to drive a tween at a
+// consistent rate regardless of frame rate or dropped frames.
+//
+// AnimatedSlide can't simply change Update's signature to take a dt
+// parameter, since a type can't have two methods both named Update
+// with different signatures; UpdateAnimated is the dt-aware sibling
+// that a dt-aware driver calls instead. StaticDeck, ChainDeck and
+// presentation.Update all check whether the Slide they're about to
+// update implements AnimatedSlide and, if so, call UpdateAnimated with
+// the real dt instead of calling Update.
+type AnimatedSlide interface {
+	Slide
+
+	// UpdateAnimated is called instead of Update by a dt-aware driver,
+	// with dt set to the elapsed time since the previous tick.
+	UpdateAnimated(dt time.Duration) error
+}
+
+// SlideFunc returns an AnimatedSlide built from draw and per-frame
+// update functions, the dt-aware analog of Dynamic. Its plain Update
+// (used if invoked by a driver that isn't dt-aware) assumes tickDelta
+// has passed, per Slide.Update's own documented contract.
+func SlideFunc(draw func(screen *ebiten.Image), update func(dt time.Duration) error) AnimatedSlide {
+	return slideFunc{draw, update}
+}
+
+type slideFunc struct {
+	draw   func(screen *ebiten.Image)
+	update func(dt time.Duration) error
+}
+
+func (s slideFunc) Draw(screen *ebiten.Image) {
+	s.draw(screen)
+}
+
+func (s slideFunc) Update() error {
+	return s.update(tickDelta)
+}
+
+func (s slideFunc) UpdateAnimated(dt time.Duration) error {
+	return s.update(dt)
+}
+
+// Transition represents an in-progress animated switch between two
+// slides. StaticDeck and ChainDeck hold a Transition while one is
+// playing back, and report DeckBusy from Next and Prev until it
+// finishes.
+type Transition interface {
+	// Draw renders the transition's current frame, typically by
+	// compositing the outgoing and incoming slides.
+	Draw(screen *ebiten.Image)
+
+	// Update advances the transition by dt. done is true once the
+	// transition has played all the way through, at which point the
+	// deck it belongs to switches over to the incoming slide.
+	Update(dt time.Duration) (done bool, err error)
+}
+
+// Fade returns a transition constructor that cross-fades from one
+// slide to another over dur.
+func Fade(dur time.Duration) func(from, to Slide) Transition {
+	return func(from, to Slide) Transition {
+		return &fadeTransition{from: from, to: to, dur: dur}
+	}
+}
+
+type fadeTransition struct {
+	from, to     Slide
+	dur, elapsed time.Duration
+	buf          *ebiten.Image
+}
+
+func (t *fadeTransition) Update(dt time.Duration) (bool, error) {
+	if err := t.from.Update(); err != nil {
+		return false, err
+	}
+	if err := t.to.Update(); err != nil {
+		return false, err
+	}
+	t.elapsed += dt
+	return t.elapsed >= t.dur, nil
+}
+
+func (t *fadeTransition) Draw(screen *ebiten.Image) {
+	if t.buf == nil {
+		b := screen.Bounds()
+		t.buf = ebiten.NewImage(b.Dx(), b.Dy())
+	}
+	p := ease.EaseInOut(progress(t.elapsed, t.dur))
+
+	t.buf.Clear()
+	t.from.Draw(t.buf)
+	var opts ebiten.DrawImageOptions
+	opts.ColorScale.ScaleAlpha(float32(1 - p))
+	screen.DrawImage(t.buf, &opts)
+
+	t.buf.Clear()
+	t.to.Draw(t.buf)
+	opts.ColorScale.Reset()
+	opts.ColorScale.ScaleAlpha(float32(p))
+	screen.DrawImage(t.buf, &opts)
+}
+
+// SlideLeft returns a transition constructor in which the incoming
+// slide slides in from the right over the outgoing slide, over dur.
+func SlideLeft(dur time.Duration) func(from, to Slide) Transition {
+	return func(from, to Slide) Transition {
+		return &slideTransition{from: from, to: to, dur: dur, dir: -1}
+	}
+}
+
+// SlideRight returns a transition constructor in which the incoming
+// slide slides in from the left over the outgoing slide, over dur.
+func SlideRight(dur time.Duration) func(from, to Slide) Transition {
+	return func(from, to Slide) Transition {
+		return &slideTransition{from: from, to: to, dur: dur, dir: 1}
+	}
+}
+
+// PushLeft returns a transition constructor in which the incoming
+// slide pushes the outgoing slide off to the left, over dur.
+func PushLeft(dur time.Duration) func(from, to Slide) Transition {
+	return func(from, to Slide) Transition {
+		return &slideTransition{from: from, to: to, dur: dur, dir: -1, push: true}
+	}
+}
+
+// PushRight returns a transition constructor in which the incoming
+// slide pushes the outgoing slide off to the right, over dur.
+func PushRight(dur time.Duration) func(from, to Slide) Transition {
+	return func(from, to Slide) Transition {
+		return &slideTransition{from: from, to: to, dur: dur, dir: 1, push: true}
+	}
+}
+
+// slideTransition implements SlideLeft, SlideRight, PushLeft and
+// PushRight. dir is -1 for the leftward transitions and +1 for the
+// rightward ones. When push is false, the outgoing slide stays put and
+// is simply covered by the incoming one; when true, it's carried off
+// to the side along with the incoming slide's entrance.
+type slideTransition struct {
+	from, to       Slide
+	dur, elapsed   time.Duration
+	dir            float64
+	push           bool
+	fromBuf, toBuf *ebiten.Image
+}
+
+func (t *slideTransition) Update(dt time.Duration) (bool, error) {
+	if err := t.from.Update(); err != nil {
+		return false, err
+	}
+	if err := t.to.Update(); err != nil {
+		return false, err
+	}
+	t.elapsed += dt
+	return t.elapsed >= t.dur, nil
+}
+
+func (t *slideTransition) Draw(screen *ebiten.Image) {
+	b := screen.Bounds()
+	w := float64(b.Dx())
+	if t.toBuf == nil {
+		t.toBuf = ebiten.NewImage(b.Dx(), b.Dy())
+		if t.push {
+			t.fromBuf = ebiten.NewImage(b.Dx(), b.Dy())
+		}
+	}
+	p := ease.EaseInOut(progress(t.elapsed, t.dur))
+
+	if t.push {
+		t.fromBuf.Clear()
+		t.from.Draw(t.fromBuf)
+		var opts ebiten.DrawImageOptions
+		opts.GeoM.Translate(t.dir*p*w, 0)
+		screen.DrawImage(t.fromBuf, &opts)
+	} else {
+		t.from.Draw(screen)
+	}
+
+	t.toBuf.Clear()
+	t.to.Draw(t.toBuf)
+	var opts ebiten.DrawImageOptions
+	opts.GeoM.Translate(t.dir*(p-1)*w, 0)
+	screen.DrawImage(t.toBuf, &opts)
+}
+
+// progress returns elapsed/dur clamped to [0, 1].
+func progress(elapsed, dur time.Duration) float64 {
+	if elapsed >= dur {
+		return 1
+	}
+	return float64(elapsed) / float64(dur)
+}
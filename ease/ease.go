@@ -0,0 +1,31 @@
+// Package ease provides easing functions for building tweens over slide
+// content and transitions.
+package ease
+
+import "github.com/mknyszek/eep/geom"
+
+// Func maps a normalized time t in [0, 1] to a normalized progress
+// value, typically also in [0, 1].
+type Func func(t float64) float64
+
+// Linear returns t unmodified.
+func Linear(t float64) float64 {
+	return t
+}
+
+// EaseInOut eases in at the start and out at the end, via a smoothstep
+// cubic.
+func EaseInOut(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+// CubicBezier returns a Func that follows the Y coordinate of a cubic
+// Bezier curve anchored at (0, 0) and (1, 1) as its parameter sweeps
+// from 0 to 1, in the style of the CSS cubic-bezier() timing function.
+// p1 and p2 are the curve's two interior control points.
+func CubicBezier(p1, p2 geom.Point) Func {
+	curve := geom.Bezier3(geom.Pt(0, 0), p1, p2, geom.Pt(1, 1))
+	return func(t float64) float64 {
+		return curve.At(t).Y
+	}
+}
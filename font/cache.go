@@ -0,0 +1,377 @@
+package font
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Cache is a persistent, on-disk store of shaped-and-rasterized text
+// runs. A slideshow tends to redraw the same handful of strings (a
+// title, a few bullet points) every frame, and again every time it's
+// presented; Cache lets a Face bound to it (see Bind) skip reshaping
+// and re-rasterizing a run it's already drawn, in this run of the
+// process or a previous one.
+//
+// The zero Cache is not usable; use OpenCache.
+type Cache struct {
+	dir string
+
+	mu      sync.Mutex
+	index   map[cacheKey]*cacheEntry
+	atlas   *ebiten.Image
+	atlasSz image.Point
+	nextX   int // Shelf-packing cursor into atlas.
+	nextY   int
+	rowH    int
+	dirty   bool // Whether index or atlas has changes not yet on disk.
+}
+
+// cacheKey identifies one shaped-and-rasterized run: everything that
+// can change its output. Source is a content hash (Source.hash)
+// rather than a name or pointer, so the cache keeps working across
+// runs even if the same font ends up loaded from a different path or
+// registered under a different name. Script, Features and Variations
+// are always "" for runs keyed from a Face's own TextFace (a plain
+// Face carries none of them); they exist so the same cache can later
+// serve font.Run-shaped text, which does.
+//
+// Its fields are exported, even though the type itself isn't, so that
+// gob (which only encodes exported fields) can serialize it as a map
+// key in Cache's on-disk index.
+type cacheKey struct {
+	Source     [sourceHashSize]byte
+	Text       string
+	Size       float64
+	Direction  TextDirection
+	Language   string
+	Script     string
+	Features   string
+	Variations string
+}
+
+// cacheEntry is one cacheKey's cached shaping-and-rasterization
+// result: where its mask lives in the atlas, how far it advances, and
+// when it was last used.
+type cacheEntry struct {
+	Rect    image.Rectangle
+	Advance float64
+	ATime   time.Time
+}
+
+const (
+	cacheIndexFile = "index.gob"
+	cacheAtlasFile = "atlas.png"
+
+	atlasPad         = 1   // Padding around each packed entry, so blits never bleed into a neighbor.
+	initialAtlasSize = 256 // Starting width/height of a freshly created atlas.
+	maxAtlasSize     = 2048
+
+	// maxEntries bounds the index rather than the atlas itself: once
+	// it's full, the oldest-ATime entry is dropped to make room for
+	// the one that just missed. Its old atlas rect is simply
+	// abandoned rather than reclaimed, trading a little wasted atlas
+	// space for not having to implement a real sub-allocator.
+	maxEntries = 512
+)
+
+// OpenCache opens or creates a glyph cache rooted at dir, loading
+// whatever index and atlas a previous run left there. A missing or
+// corrupt index or atlas is treated as an empty cache rather than an
+// error, since losing a cache only costs the shaping and
+// rasterization work it would have saved.
+func OpenCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("font: opening cache %q: %w", dir, err)
+	}
+	c := &Cache{dir: dir, index: make(map[cacheKey]*cacheEntry)}
+	// The index is only meaningful alongside the atlas it points
+	// into, so a missing or corrupt atlas means starting over with an
+	// empty index too, not entries pointing nowhere.
+	if c.loadAtlas() {
+		c.loadIndex()
+		c.resumePacking()
+	}
+	return c, nil
+}
+
+// resumePacking sets the shelf-packing cursor to just past every
+// entry loaded from disk, so alloc never overwrites them.
+func (c *Cache) resumePacking() {
+	for _, e := range c.index {
+		c.nextY = max(c.nextY, e.Rect.Max.Y)
+	}
+}
+
+func (c *Cache) loadIndex() {
+	f, err := os.Open(filepath.Join(c.dir, cacheIndexFile))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	var index map[cacheKey]*cacheEntry
+	if gob.NewDecoder(f).Decode(&index) != nil {
+		return
+	}
+	c.index = index
+}
+
+func (c *Cache) loadAtlas() bool {
+	f, err := os.Open(filepath.Join(c.dir, cacheAtlasFile))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return false
+	}
+	c.atlas = ebiten.NewImageFromImage(img)
+	c.atlasSz = img.Bounds().Size()
+	return true
+}
+
+// Bind associates f with c: subsequent draws of f's text through
+// Face.TextFace's usual call sites (see Face.DrawCached) transparently
+// consult c first, falling back to normal shaping and rasterization
+// on a miss and writing the result back to c.
+func (c *Cache) Bind(f *Face) {
+	f.cache = c
+}
+
+// Prewarm shapes and rasterizes every string in strings against every
+// face in faces that's Bind'd to c, so that cost is paid once up
+// front (e.g. at slide-load time) rather than on whichever frame
+// first draws it. The index and atlas are flushed to disk once at the
+// end, rather than after each individual string as DrawCached does,
+// so warming up many strings doesn't re-encode the whole atlas once
+// per string.
+func (c *Cache) Prewarm(strings []string, faces []*Face) {
+	for _, f := range faces {
+		if f.cache != c {
+			continue
+		}
+		for _, s := range strings {
+			c.lookup(f, s, false)
+		}
+	}
+	c.mu.Lock()
+	if c.dirty {
+		c.flush()
+	}
+	c.mu.Unlock()
+}
+
+// flush writes c's index and atlas to disk. Called with c.mu held,
+// after every miss that adds or evicts an entry; the cost of a PNG
+// encode plus a gob encode is paid once per distinct run a Face ever
+// draws, not per frame.
+func (c *Cache) flush() {
+	if idxf, err := os.Create(filepath.Join(c.dir, cacheIndexFile)); err == nil {
+		gob.NewEncoder(idxf).Encode(c.index)
+		idxf.Close()
+	}
+	if c.atlas != nil {
+		if imgf, err := os.Create(filepath.Join(c.dir, cacheAtlasFile)); err == nil {
+			png.Encode(imgf, c.atlas)
+			imgf.Close()
+		}
+	}
+	c.dirty = false
+}
+
+// lookup returns the subimage of c's atlas holding runText's cached
+// mask (rasterized with f) and its advance, shaping and rasterizing it
+// first on a miss. ok is false if runText can't be cached at all (it's
+// empty, or it measures out larger than the atlas can ever hold), in
+// which case the caller should fall back to drawing runText itself.
+//
+// autoFlush writes c's index and atlas to disk immediately if this
+// call added a new entry; Prewarm passes false and flushes once after
+// a whole batch instead.
+func (c *Cache) lookup(f *Face, runText string, autoFlush bool) (sub *ebiten.Image, advance float64, ok bool) {
+	key := f.cacheKey(runText)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, hit := c.index[key]
+	if !hit {
+		var rendered bool
+		entry, rendered = c.render(f, runText)
+		if !rendered {
+			return nil, 0, false
+		}
+		c.index[key] = entry
+		c.evictLocked()
+		c.dirty = true
+	}
+	entry.ATime = time.Now()
+	if autoFlush && c.dirty {
+		c.flush()
+	}
+	return c.atlas.SubImage(entry.Rect).(*ebiten.Image), entry.Advance, true
+}
+
+// render shapes and rasterizes runText with f, packs the resulting
+// mask into c's atlas, and returns the new entry. Reports false if
+// runText is empty or too large to ever fit in the atlas (see
+// maxAtlasSize): there's nothing useful to cache either way.
+func (c *Cache) render(f *Face, runText string) (*cacheEntry, bool) {
+	if runText == "" || runText == "\n" {
+		return nil, false
+	}
+	tf := f.TextFace()
+	adv := text.Advance(runText, tf)
+	lineSz := f.LineSize(0)
+
+	w, h := int(math.Ceil(adv)), int(math.Ceil(lineSz))
+	switch f.direction {
+	case DirectionTopToBottomAndLeftToRight, DirectionTopToBottomAndRightToLeft:
+		w, h = h, w
+	}
+	w, h = max(w, 1), max(h, 1)
+	if w+atlasPad > maxAtlasSize || h+atlasPad > maxAtlasSize {
+		// Too big to ever fit, however much the atlas grows or is
+		// evicted; drawing it directly every time beats looping
+		// forever trying to pack it in alloc.
+		return nil, false
+	}
+
+	mask := ebiten.NewImage(w, h)
+	var opts text.DrawOptions
+	opts.ColorScale.ScaleWithColor(color.White)
+	text.Draw(mask, runText, tf, &opts)
+
+	rect := c.alloc(w, h)
+	var blit ebiten.DrawImageOptions
+	blit.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+	c.atlas.DrawImage(mask, &blit)
+
+	return &cacheEntry{Rect: rect, Advance: adv}, true
+}
+
+// alloc reserves a w-by-h rectangle in c's atlas via simple shelf
+// packing, growing (or evicting into) the atlas as needed.
+func (c *Cache) alloc(w, h int) image.Rectangle {
+	w, h = w+atlasPad, h+atlasPad
+	for {
+		if c.atlas == nil {
+			c.growAtlas()
+			continue
+		}
+		if c.nextX+w > c.atlasSz.X {
+			c.nextX, c.nextY, c.rowH = 0, c.nextY+c.rowH, 0
+		}
+		if c.nextX+w <= c.atlasSz.X && c.nextY+h <= c.atlasSz.Y {
+			break
+		}
+		c.growAtlas()
+	}
+	r := image.Rect(c.nextX, c.nextY, c.nextX+w-atlasPad, c.nextY+h-atlasPad)
+	c.nextX += w
+	c.rowH = max(c.rowH, h)
+	return r
+}
+
+// growAtlas doubles the size of c's atlas (creating the initial one
+// if it's nil), preserving existing content. Once doubling would pass
+// maxAtlasSize, it instead evicts the cache's oldest entries and
+// resets the packing cursor, reusing the atlas at its current size.
+func (c *Cache) growAtlas() {
+	if c.atlas != nil && c.atlasSz.X >= maxAtlasSize {
+		c.evictOldest(len(c.index) / 4)
+		c.nextX, c.nextY, c.rowH = 0, 0, 0
+		return
+	}
+	size := initialAtlasSize
+	if c.atlas != nil {
+		size = c.atlasSz.X * 2
+	}
+	next := ebiten.NewImage(size, size)
+	if c.atlas != nil {
+		var opts ebiten.DrawImageOptions
+		next.DrawImage(c.atlas, &opts)
+	}
+	c.atlas, c.atlasSz = next, image.Pt(size, size)
+}
+
+// evictLocked drops the single oldest-ATime entry once the index
+// grows past maxEntries. Called with c.mu held.
+func (c *Cache) evictLocked() {
+	if len(c.index) <= maxEntries {
+		return
+	}
+	c.evictOldest(1)
+}
+
+// evictOldest removes the n oldest-ATime entries from c.index. Their
+// atlas rects are simply abandoned; see maxEntries.
+func (c *Cache) evictOldest(n int) {
+	for ; n > 0; n-- {
+		var oldestKey cacheKey
+		var oldest *cacheEntry
+		for k, e := range c.index {
+			if oldest == nil || e.ATime.Before(oldest.ATime) {
+				oldestKey, oldest = k, e
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		delete(c.index, oldestKey)
+	}
+}
+
+// cacheKey builds the cacheKey for runText rendered with f. Source
+// folds in every one of f's sources, not just the primary, since two
+// Faces that only differ in Fallbacks can render the same string
+// differently (e.g. one glyph falls through to a different Fallback)
+// despite sharing a primary Source, size, direction and language.
+func (f *Face) cacheKey(runText string) cacheKey {
+	h := sha256.New()
+	for _, src := range f.sources {
+		h.Write(src.hash[:])
+	}
+	var source [sourceHashSize]byte
+	h.Sum(source[:0])
+	return cacheKey{
+		Source:    source,
+		Text:      runText,
+		Size:      f.size,
+		Direction: f.direction,
+		Language:  f.language.String(),
+	}
+}
+
+// DrawCached draws runText at (x, y) in dst using f's Face, going
+// through f's bound Cache (see Cache.Bind) if it has one, and reports
+// the advance runText draws with if it handled the draw. ok is false
+// if f has no Cache bound, or runText can't be served from the cache
+// (e.g. it's a bare "\n", or too large to ever fit in the atlas); the
+// caller should fall back to its own shaping and text.Draw call.
+func (f *Face) DrawCached(dst *ebiten.Image, runText string, x, y float64, c color.Color) (advance float64, ok bool) {
+	if f.cache == nil {
+		return 0, false
+	}
+	sub, adv, ok := f.cache.lookup(f, runText, true)
+	if !ok {
+		return 0, false
+	}
+	var opts ebiten.DrawImageOptions
+	opts.GeoM.Translate(x, y)
+	opts.ColorScale.ScaleWithColor(c)
+	dst.DrawImage(sub, &opts)
+	return adv, true
+}
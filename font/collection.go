@@ -0,0 +1,136 @@
+package font
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adrg/sysfont"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// sourcesFromFile loads every font embedded in filename: a single
+// Source for a standalone OTF/TTF, or one per embedded font for a
+// TTC/OTC collection (see NewSourcesFromCollectionFile). The returned
+// names are font family names, "" where a font (or a whole standalone
+// file) doesn't have one.
+func sourcesFromFile(filename string) ([]Source, []string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ttc", ".otc":
+		return NewSourcesFromCollectionFile(filename)
+	default:
+		s, err := NewSourceFromFile(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []Source{s}, []string{""}, nil
+	}
+}
+
+// loadSystemFont loads sf, which may be a single-face OTF/TTF or a
+// TTC/OTC collection. For a collection, every embedded font is parsed
+// and registered under a unique name derived from its family name (see
+// RegisterSource), and the first entry whose family matches sf.Name is
+// returned, since that's the one sysfont's fuzzy match picked out.
+func loadSystemFont(sf *sysfont.Font) (Source, error) {
+	srcs, names, err := sourcesFromFile(sf.Filename)
+	if err != nil {
+		return Source{}, err
+	}
+	if len(srcs) == 0 {
+		return Source{}, fmt.Errorf("font: %s has no embedded fonts", sf.Filename)
+	}
+	if len(srcs) == 1 {
+		return srcs[0], nil
+	}
+	best := srcs[0]
+	bestFound := false
+	seen := make(map[string]bool, len(srcs))
+	for i, src := range srcs {
+		base := names[i]
+		if base == "" {
+			base = sf.Name
+		}
+		// A collection's entries (e.g. a TTC's Regular/Bold/Italic
+		// faces) commonly share the same family name, so registering
+		// each under base directly would have later entries silently
+		// overwrite earlier ones; disambiguate by index whenever a
+		// name repeats.
+		name := base
+		if seen[base] {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		seen[base] = true
+		RegisterSource(name, src)
+		if !bestFound && strings.EqualFold(names[i], sf.Name) {
+			best, bestFound = src, true
+		}
+	}
+	return best, nil
+}
+
+// NewSourcesFromCollectionFile is like NewSourcesFromCollection, but
+// reads from a TTC/OTC file on disk.
+func NewSourcesFromCollectionFile(filename string) ([]Source, []string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewSourcesFromCollection(f, info.Size())
+}
+
+// NewSourcesFromCollection parses r as a TrueType/OpenType font
+// collection (TTC/OTC) of the given size, and returns one Source per
+// font embedded in it, in file order, alongside each one's font family
+// name. Many system fonts, notably on macOS and Windows (Helvetica.ttc,
+// Cambria.ttc), only ship this way.
+//
+// size bounds how much of r is read: exactly size bytes starting at
+// offset 0, so a reader backed by a larger underlying file (or one
+// whose length wasn't re-checked since Stat) can't leak trailing data
+// into the parse.
+func NewSourcesFromCollection(r io.ReaderAt, size int64) ([]Source, []string, error) {
+	b, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, nil, err
+	}
+	gtfSrcs, err := text.NewGoTextFaceSourcesFromCollection(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, fmt.Errorf("font: parsing collection: %w", err)
+	}
+	srcs := make([]Source, len(gtfSrcs))
+	names := make([]string, len(gtfSrcs))
+	for i, src := range gtfSrcs {
+		srcs[i] = Source{src: src, hash: collectionEntryHash(b, i)}
+		names[i] = src.Metadata().Family
+	}
+	return srcs, names, nil
+}
+
+// collectionEntryHash derives Source.hash for the i'th font parsed out
+// of a collection's raw bytes b. The collection is parsed as a whole
+// (text.NewGoTextFaceSourcesFromCollection takes the whole file, not
+// per-entry byte ranges), so there's no single embedded font's bytes to
+// hash in isolation; folding the entry's index into a hash of the whole
+// file is enough to keep every entry's Source.hash distinct, which is
+// all Cache relies on.
+func collectionEntryHash(b []byte, i int) [sourceHashSize]byte {
+	h := sha256.New()
+	h.Write(b)
+	var idx [8]byte
+	binary.BigEndian.PutUint64(idx[:], uint64(i))
+	h.Write(idx[:])
+	var sum [sourceHashSize]byte
+	h.Sum(sum[:0])
+	return sum
+}
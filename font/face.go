@@ -2,6 +2,7 @@ package font
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"io"
 	"os"
 	"runtime"
@@ -14,18 +15,62 @@ import (
 )
 
 // Face represents a configuration of the display of text.
+//
+// A Face may be backed by more than one Source, configured via
+// Fallbacks: glyphs missing from the primary Source are drawn from the
+// first fallback Source that has them (e.g. mixing a Latin body font
+// with CJK and emoji fonts).
 type Face struct {
-	face text.GoTextFace
+	size      float64
+	direction TextDirection
+	language  language.Tag
+
+	sources []Source          // sources[0] is the primary Source; the rest are Fallbacks, in priority order.
+	faces   []text.GoTextFace // faces[i] wraps sources[i], sharing size, direction and language.
+	face    text.Face         // What TextFace returns: &faces[0], or a *text.MultiFace over all of faces.
+
+	cache *Cache // Set by Cache.Bind; consulted by DrawCached.
 }
 
 // NewFace creates a new font face that can be used to draw text.
 func NewFace(src Source, size float64, opts ...Option) *Face {
-	f := new(Face)
-	f.face.Source = src.src
-	f.face.Size = size
+	f := &Face{size: size, sources: []Source{src}}
+	for _, o := range opts {
+		o.f(f)
+	}
+	f.rebuild()
 	return f
 }
 
+// rebuild regenerates faces and face from f's current sources, size,
+// direction and language. Called whenever any of those change.
+func (f *Face) rebuild() {
+	f.faces = make([]text.GoTextFace, len(f.sources))
+	for i, src := range f.sources {
+		f.faces[i] = text.GoTextFace{
+			Source:    src.src,
+			Size:      f.size,
+			Direction: f.direction,
+			Language:  f.language,
+		}
+	}
+	if len(f.faces) == 1 {
+		f.face = &f.faces[0]
+		return
+	}
+	multi := make([]text.Face, len(f.faces))
+	for i := range f.faces {
+		multi[i] = &f.faces[i]
+	}
+	mf, err := text.NewMultiFace(multi...)
+	if err != nil {
+		// All of f.faces share f.direction, so NewMultiFace's only
+		// error condition (disagreeing directions) can't occur.
+		panic(err)
+	}
+	f.face = mf
+}
+
 // Option represents additional optional configuration for a Face.
 type Option struct {
 	f func(*Face)
@@ -43,25 +88,39 @@ const (
 // Direction sets the Face's rendering direction.
 func Direction(d TextDirection) Option {
 	return Option{func(f *Face) {
-		f.face.Direction = d
+		f.direction = d
 	}}
 }
 
 // Language sets the Face's language hint.
 func Language(t language.Tag) Option {
 	return Option{func(f *Face) {
-		f.face.Language = t
+		f.language = t
 	}}
 }
 
-// Source returns the Source for the Face.
+// Fallbacks adds srcs, in order, as fallback sources for glyphs the
+// primary Source (and any earlier fallback) can't render. Each
+// fallback inherits the Face's size, direction and language.
+func Fallbacks(srcs ...Source) Option {
+	return Option{func(f *Face) {
+		f.sources = append(f.sources, srcs...)
+	}}
+}
+
+// Source returns the primary Source for the Face.
 func (f *Face) Source() Source {
-	return Source{f.face.Source}
+	return f.sources[0]
 }
 
 // Size returns the size of the Face.
 func (f *Face) Size() float64 {
-	return f.face.Size
+	return f.size
+}
+
+// Direction returns the Face's rendering direction.
+func (f *Face) Direction() TextDirection {
+	return f.direction
 }
 
 // Resize returns a new Face with all the same features except with the
@@ -69,7 +128,8 @@ func (f *Face) Size() float64 {
 func (f *Face) Resize(size float64) *Face {
 	g := new(Face)
 	*g = *f
-	g.face.Size = size
+	g.size = size
+	g.rebuild()
 	return g
 }
 
@@ -80,20 +140,74 @@ func (f *Face) Resize(size float64) *Face {
 // the line size.
 func (f *Face) LineSize(lineSpacing float64) float64 {
 	m := f.face.Metrics()
-	if f.face.Direction == text.DirectionLeftToRight || f.face.Direction == text.DirectionRightToLeft {
+	if f.direction == text.DirectionLeftToRight || f.direction == text.DirectionRightToLeft {
 		return (m.HAscent + m.HDescent) * (lineSpacing + 1.0)
 	}
 	return (m.VAscent + m.VDescent) * (lineSpacing + 1.0)
 }
 
-// TextFace returns the underlying Ebiten GoTextFace.
+// TextFace returns the underlying Ebiten text.Face used to draw with
+// this Face: a *text.GoTextFace if it has no Fallbacks, or a
+// *text.MultiFace trying the primary Source and each fallback in order
+// otherwise.
 //
 // Mutating the result will also mutate Face.
-func (f *Face) TextFace() *text.GoTextFace {
-	return &f.face
+//
+// Callers that draw the same strings repeatedly (e.g. text/box.go's
+// drawString) should prefer DrawCached, which reshapes and
+// rasterizes through TextFace only on a cache miss; see Cache.Bind.
+func (f *Face) TextFace() text.Face {
+	return f.face
+}
+
+// HasGlyph reports whether r can be rendered by this Face, i.e. it has
+// a glyph for r in its primary Source or one of its Fallbacks. Callers
+// needing to render text that may contain characters outside a single
+// Face's coverage, such as emoji or a mixture of scripts, can use this
+// to pick a suitable fallback Face; see Style.Fallbacks in the text
+// package for the same idea applied per-Style instead of per-Face.
+func (f *Face) HasGlyph(r rune) bool {
+	for i := range f.faces {
+		if f.faces[i].HasGlyph(r) {
+			return true
+		}
+	}
+	return false
 }
 
-var sourceRegistry sync.Map // string -> weak.Pointer[text.GoTextFaceSource]
+var sourceRegistry sync.Map // string -> registeredSource
+
+// registeredSource is what sourceRegistry stores: a weak pointer to
+// the underlying text.GoTextFaceSource, plus the content hash a
+// reconstructed Source needs (see Source.hash) that the weak pointer
+// alone can't recover.
+type registeredSource struct {
+	wp   weak.Pointer[text.GoTextFaceSource]
+	hash [sourceHashSize]byte
+}
+
+// SystemFont describes a font installed on the system, as found by
+// ListSystemFonts.
+type SystemFont struct {
+	Name     string
+	Filename string
+}
+
+// ListSystemFonts returns every font sysfont was able to find on the
+// system.
+//
+// Safe to call from multiple goroutines simultaneously.
+func ListSystemFonts() []SystemFont {
+	sysFonts.mu.Lock()
+	fonts := sysFonts.finder.List()
+	sysFonts.mu.Unlock()
+
+	out := make([]SystemFont, len(fonts))
+	for i, f := range fonts {
+		out[i] = SystemFont{Name: f.Name, Filename: f.Filename}
+	}
+	return out
+}
 
 // FindSource looks first for a pre-registered font source, registered by RegisterSource,
 // and if that fails, searches the system for related fonts (via fuzzy match), then
@@ -103,8 +217,9 @@ var sourceRegistry sync.Map // string -> weak.Pointer[text.GoTextFaceSource]
 func FindSource(name string) (Source, bool) {
 	// Try the sourceRegistry.
 	if a, ok := sourceRegistry.Load(name); ok {
-		if s := a.(weak.Pointer[text.GoTextFaceSource]).Value(); s != nil {
-			return Source{s}, true
+		reg := a.(registeredSource)
+		if s := reg.wp.Value(); s != nil {
+			return Source{src: s, hash: reg.hash}, true
 		}
 	}
 
@@ -119,13 +234,14 @@ func FindSource(name string) (Source, bool) {
 
 	// Try the sourceRegistry for the full name.
 	if a, ok := sourceRegistry.Load(sf.Name); ok {
-		if s := a.(weak.Pointer[text.GoTextFaceSource]).Value(); s != nil {
-			return Source{s}, true
+		reg := a.(registeredSource)
+		if s := reg.wp.Value(); s != nil {
+			return Source{src: s, hash: reg.hash}, true
 		}
 	}
 
 	// Load the system font.
-	s, err := NewSourceFromFile(sf.Filename)
+	s, err := loadSystemFont(sf)
 	if err != nil {
 		return Source{}, false
 	}
@@ -133,25 +249,100 @@ func FindSource(name string) (Source, bool) {
 	return s, true
 }
 
+// FindSourceForLanguage is like FindSource, but additionally checks
+// that the result can render tag's script. If name's best match can't,
+// it walks ListSystemFonts looking for one that can, registering
+// whichever Source it settles on under its filename so repeat lookups
+// for the same font are free. If no installed font covers tag's
+// script, this falls back to whatever FindSource(name) returned.
+//
+// Safe to call from multiple goroutines simultaneously.
+func FindSourceForLanguage(name string, tag language.Tag) (Source, bool) {
+	s, ok := FindSource(name)
+	if ok && sourceCoversLanguage(s, tag) {
+		return s, true
+	}
+	for _, sf := range ListSystemFonts() {
+		if a, ok := sourceRegistry.Load(sf.Filename); ok {
+			reg := a.(registeredSource)
+			if cand := reg.wp.Value(); cand != nil {
+				candSrc := Source{src: cand, hash: reg.hash}
+				if sourceCoversLanguage(candSrc, tag) {
+					return candSrc, true
+				}
+				continue
+			}
+		}
+		srcs, _, err := sourcesFromFile(sf.Filename)
+		if err != nil || len(srcs) == 0 {
+			continue
+		}
+		cand := srcs[0] // Collections are checked by their first embedded font only.
+		if !sourceCoversLanguage(cand, tag) {
+			continue
+		}
+		RegisterSource(sf.Filename, cand)
+		return cand, true
+	}
+	return s, ok
+}
+
+// scriptSample maps an ISO 15924 script code to a rune that's
+// essentially guaranteed to appear in any font actually intended to
+// support that script, for sourceCoversLanguage's coverage check.
+var scriptSample = map[string]rune{
+	"Arab": 0x0627, // Arabic letter Alef.
+	"Cyrl": 0x0410, // Cyrillic capital A.
+	"Deva": 0x0905, // Devanagari letter A.
+	"Grek": 0x0391, // Greek capital Alpha.
+	"Hang": 0xAC00, // Hangul syllable Ga.
+	"Hans": 0x4E2D, // CJK ideograph 中.
+	"Hant": 0x4E2D,
+	"Hebr": 0x05D0, // Hebrew letter Alef.
+	"Jpan": 0x3042, // Hiragana A.
+	"Kore": 0xAC00,
+	"Mong": 0x1820, // Mongolian letter A.
+	"Mymr": 0x1000, // Myanmar letter Ka.
+	"Thai": 0x0E01, // Thai character Ko Kai.
+}
+
+// sourceCoversLanguage reports whether s has a glyph for tag's script.
+// Scripts with no entry in scriptSample (e.g. Latin) are assumed
+// covered, since sysfont doesn't report OpenType script/lang coverage
+// itself and nearly every font covers Latin.
+func sourceCoversLanguage(s Source, tag language.Tag) bool {
+	script, _ := tag.Script()
+	r, ok := scriptSample[script.String()]
+	if !ok {
+		return true
+	}
+	return NewFace(s, 16).HasGlyph(r)
+}
+
 // RegisterSource adds a source to the registry under the provided name.
 //
 // Overrides any system fonts.
 // Safe to call from multiple goroutines simultaneously.
 func RegisterSource(name string, s Source) {
-	wp := weak.Make(s.src)
+	reg := registeredSource{wp: weak.Make(s.src), hash: s.hash}
 	type entry struct {
 		name string
-		wp   weak.Pointer[text.GoTextFaceSource]
+		reg  registeredSource
 	}
 	runtime.AddCleanup(s.src, func(e entry) {
-		sourceRegistry.CompareAndDelete(e.name, e.wp)
-	}, entry{name, wp})
-	sourceRegistry.Store(name, wp)
+		sourceRegistry.CompareAndDelete(e.name, e.reg)
+	}, entry{name, reg})
+	sourceRegistry.Store(name, reg)
 }
 
+// sourceHashSize is the width of Source.hash, a content hash used to
+// key font.Cache entries.
+const sourceHashSize = sha256.Size
+
 // Source is a font source used to create Faces, which are used to draw text.
 type Source struct {
-	src *text.GoTextFaceSource
+	src  *text.GoTextFaceSource
+	hash [sourceHashSize]byte // Content hash of the font file, used by Cache.
 }
 
 // NewSourceFromBytes creates a new font source from the bytes of an OTF or TTF file.
@@ -171,11 +362,15 @@ func NewSourceFromFile(filename string) (Source, error) {
 
 // NewSource creates a new font source from an io.Reader whose stream must be an OTF or TTF-formatted file.
 func NewSource(r io.Reader) (Source, error) {
-	src, err := text.NewGoTextFaceSource(r)
+	b, err := io.ReadAll(r)
 	if err != nil {
 		return Source{}, err
 	}
-	return Source{src: src}, nil
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(b))
+	if err != nil {
+		return Source{}, err
+	}
+	return Source{src: src, hash: sha256.Sum256(b)}, nil
 }
 
 // DefaultSource is a Source guaranteed to exist that may be used as a fallback.
@@ -188,7 +383,7 @@ var sysFonts struct {
 
 func init() {
 	// Set up a font finder.
-	sysFonts.finder = sysfont.NewFinder(&sysfont.FinderOpts{Extensions: []string{".ttf", ".otf"}})
+	sysFonts.finder = sysfont.NewFinder(&sysfont.FinderOpts{Extensions: []string{".ttf", ".otf", ".ttc", ".otc"}})
 
 	// Try to set a default font.
 	for _, name := range []string{"Arial", "Helvetica", "Times New Roman", "Times", "Courier New", "Courier"} {
@@ -198,6 +393,21 @@ func init() {
 			break
 		}
 	}
+	if DefaultSource.src == nil {
+		// None of the usual suspects exist on this system (e.g. a
+		// minimal Linux install with no Microsoft-compatible fonts);
+		// fall back to whatever sysfont could find at all, rather
+		// than leaving DefaultSource unusable.
+		for _, sf := range ListSystemFonts() {
+			srcs, _, err := sourcesFromFile(sf.Filename)
+			if err != nil || len(srcs) == 0 {
+				continue
+			}
+			RegisterSource(sf.Filename, srcs[0])
+			DefaultSource = srcs[0]
+			break
+		}
+	}
 	if DefaultSource.src == nil {
 		println("failed to set a default font")
 	}
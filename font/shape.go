@@ -0,0 +1,181 @@
+package font
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/text/language"
+)
+
+// Run is a single run of text sharing one Face, direction, script and
+// language: a maximal span that can be shaped and drawn on its own.
+// Rich text that mixes multiple scripts or directions on one line
+// (e.g. English interspersed with Arabic, or vertical Mongolian) is
+// built from a []Run, one per span, rather than a single Face.
+type Run struct {
+	Text string
+	Face *Face
+
+	// Direction, Language and Script override the Face's own
+	// direction and language for this run; Script, an ISO 15924 code
+	// (e.g. "Arab", "Mong", "Thai"), overrides whatever script
+	// Language implies. Leave Script empty to use Language's own
+	// script.
+	Direction TextDirection
+	Language  language.Tag
+	Script    string
+
+	// Features is an OpenType feature string (e.g. "liga,-kern")
+	// forwarded to the shaper for this run.
+	Features string
+
+	// Variations is an OpenType variation-axis string (e.g.
+	// "wght=700,wdth=80") forwarded to the shaper for this run, for
+	// variable fonts.
+	Variations string
+}
+
+// ShapedGlyph is a single positioned glyph produced by Shape.
+type ShapedGlyph struct {
+	Run  int   // Index into the []Run Shape was called with.
+	Face *Face // The run's Face.
+
+	// X and Y are the glyph's origin, relative to the start of the
+	// whole shaped line.
+	X, Y float64
+
+	// StartInRun and EndInRun are the byte range within the run's
+	// Text that this glyph corresponds to, for hit-testing a click or
+	// selection range back to source text.
+	StartInRun, EndInRun int
+}
+
+// Shape lays runs out one after another along their shared primary
+// axis and returns the positioned glyphs, suitable for hit-testing
+// (map a point back to StartInRun/EndInRun) or for inline drawing
+// glyph-by-glyph rather than through text.Draw.
+//
+// runs must already be in visual order; Shape performs no
+// bidirectional reordering of its own; a caller mixing LTR and RTL
+// runs is responsible for ordering them left-to-right as they should
+// appear on screen.
+func Shape(runs []Run) []ShapedGlyph {
+	var out []ShapedGlyph
+	var x, y float64
+	for i, run := range runs {
+		face := RunFace(run)
+
+		var glyphs []text.Glyph
+		glyphs = text.AppendGlyphs(glyphs, run.Text, face, nil)
+		for _, g := range glyphs {
+			out = append(out, ShapedGlyph{
+				Run:        i,
+				Face:       run.Face,
+				X:          x + g.OriginX + g.OriginOffsetX,
+				Y:          y + g.OriginY + g.OriginOffsetY,
+				StartInRun: g.StartIndexInBytes,
+				EndInRun:   g.EndIndexInBytes,
+			})
+		}
+
+		adv := text.Advance(run.Text, face)
+		switch run.Direction {
+		case DirectionLeftToRight:
+			x += adv
+		case DirectionRightToLeft:
+			x -= adv
+		case DirectionTopToBottomAndLeftToRight, DirectionTopToBottomAndRightToLeft:
+			y += adv
+		}
+	}
+	return out
+}
+
+// RunFace builds the text.Face Shape and other run-drawing callers use
+// for run: one text.GoTextFace per Source in run.Face (or a
+// *text.MultiFace over all of them if there's more than one, exactly
+// like Face.rebuild), each inheriting run.Face's size and sources but
+// configured with run's own direction, language and script instead of
+// run.Face's, and with run.Features and run.Variations applied via
+// GoTextFace's SetFeature/SetVariation.
+func RunFace(run Run) text.Face {
+	lang := run.Language
+	if run.Script != "" {
+		if script, err := language.ParseScript(run.Script); err == nil {
+			if composed, err := language.Compose(lang, script); err == nil {
+				lang = composed
+			}
+		}
+	}
+
+	faces := make([]text.Face, len(run.Face.sources))
+	for i, src := range run.Face.sources {
+		gf := &text.GoTextFace{
+			Source:    src.src,
+			Size:      run.Face.size,
+			Direction: run.Direction,
+			Language:  lang,
+		}
+		applyFeatures(gf, run.Features)
+		applyVariations(gf, run.Variations)
+		faces[i] = gf
+	}
+	if len(faces) == 1 {
+		return faces[0]
+	}
+	mf, err := text.NewMultiFace(faces...)
+	if err != nil {
+		// All of faces share run.Direction, so NewMultiFace's only
+		// error condition (disagreeing directions) can't occur.
+		panic(err)
+	}
+	return mf
+}
+
+// applyFeatures parses an OpenType feature string (e.g. "liga,-kern")
+// and applies each entry to f via SetFeature: a tag prefixed with "-"
+// is disabled (value 0), any other tag is enabled (value 1). Entries
+// that aren't a valid 4-character tag are skipped.
+func applyFeatures(f *text.GoTextFace, features string) {
+	for _, tok := range strings.Split(features, ",") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		value := uint32(1)
+		if rest, ok := strings.CutPrefix(tok, "-"); ok {
+			tok, value = rest, 0
+		}
+		tag, err := text.ParseTag(tok)
+		if err != nil {
+			continue
+		}
+		f.SetFeature(tag, value)
+	}
+}
+
+// applyVariations parses an OpenType variation-axis string (e.g.
+// "wght=700,wdth=80") and applies each axis to f via SetVariation.
+// Entries that aren't a valid "tag=value" pair are skipped.
+func applyVariations(f *text.GoTextFace, variations string) {
+	for _, tok := range strings.Split(variations, ",") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(tok, "=")
+		if !ok {
+			continue
+		}
+		tag, err := text.ParseTag(name)
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(val, 32)
+		if err != nil {
+			continue
+		}
+		f.SetVariation(tag, float32(v))
+	}
+}
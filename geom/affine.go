@@ -0,0 +1,178 @@
+package geom
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Affine is a 2D affine transformation matrix:
+//
+//	| A B Tx |
+//	| C D Ty |
+//
+// applied to a point (x, y) as (A*x + B*y + Tx, C*x + D*y + Ty).
+type Affine struct {
+	A, B, Tx float64
+	C, D, Ty float64
+}
+
+// IdentityAffine is the identity transform.
+var IdentityAffine = Affine{A: 1, D: 1}
+
+// Translation returns an Affine that translates by v.
+func Translation(v Vector) Affine {
+	return Affine{A: 1, D: 1, Tx: v.X, Ty: v.Y}
+}
+
+// Rotation returns an Affine that rotates anticlockwise by rad radians
+// about the origin.
+func Rotation(rad float64) Affine {
+	cos := math.Cos(rad)
+	sin := math.Sin(rad)
+	return Affine{A: cos, B: -sin, C: sin, D: cos}
+}
+
+// Scale returns an Affine that scales by sx and sy about the origin.
+func Scale(sx, sy float64) Affine {
+	return Affine{A: sx, D: sy}
+}
+
+// Shear returns an Affine that shears by factors kx and ky about the
+// origin.
+func Shear(kx, ky float64) Affine {
+	return Affine{A: 1, B: kx, C: ky, D: 1}
+}
+
+// Mul returns the composition of a followed by b: applying a.Mul(b) to
+// a point is equivalent to applying a to it, then applying b to the
+// result.
+func (a Affine) Mul(b Affine) Affine {
+	return Affine{
+		A: b.A*a.A + b.B*a.C, B: b.A*a.B + b.B*a.D, Tx: b.A*a.Tx + b.B*a.Ty + b.Tx,
+		C: b.C*a.A + b.D*a.C, D: b.C*a.B + b.D*a.D, Ty: b.C*a.Tx + b.D*a.Ty + b.Ty,
+	}
+}
+
+// Compose concatenates the given transforms in order, so that applying
+// the result to a point is equivalent to applying each of xs to it in
+// turn.
+func Compose(xs ...Affine) Affine {
+	result := IdentityAffine
+	for _, x := range xs {
+		result = result.Mul(x)
+	}
+	return result
+}
+
+// Inverse returns the inverse of a, and false if a has no inverse (a
+// zero determinant, e.g. a zero scale factor).
+func (a Affine) Inverse() (Affine, bool) {
+	det := a.A*a.D - a.B*a.C
+	if det == 0 {
+		return Affine{}, false
+	}
+	invDet := 1 / det
+	ia, ib := a.D*invDet, -a.B*invDet
+	ic, id := -a.C*invDet, a.A*invDet
+	return Affine{
+		A: ia, B: ib, Tx: -(ia*a.Tx + ib*a.Ty),
+		C: ic, D: id, Ty: -(ic*a.Tx + id*a.Ty),
+	}, true
+}
+
+// Apply transforms p by a.
+func (a Affine) Apply(p Point) Point {
+	return Point{a.A*p.X + a.B*p.Y + a.Tx, a.C*p.X + a.D*p.Y + a.Ty}
+}
+
+// ApplyVector transforms v by a's linear part, ignoring translation.
+func (a Affine) ApplyVector(v Vector) Vector {
+	return Vector{a.A*v.X + a.B*v.Y, a.C*v.X + a.D*v.Y}
+}
+
+// ToGeoM converts a to an ebiten.GeoM, for use with ebiten's drawing APIs.
+func (a Affine) ToGeoM() ebiten.GeoM {
+	var g ebiten.GeoM
+	g.SetElement(0, 0, a.A)
+	g.SetElement(0, 1, a.B)
+	g.SetElement(0, 2, a.Tx)
+	g.SetElement(1, 0, a.C)
+	g.SetElement(1, 1, a.D)
+	g.SetElement(1, 2, a.Ty)
+	return g
+}
+
+// AffineFromGeoM converts an ebiten.GeoM to an Affine.
+func AffineFromGeoM(g ebiten.GeoM) Affine {
+	return Affine{
+		A: g.Element(0, 0), B: g.Element(0, 1), Tx: g.Element(0, 2),
+		C: g.Element(1, 0), D: g.Element(1, 1), Ty: g.Element(1, 2),
+	}
+}
+
+// Transformable is implemented by every geom type that can be moved by
+// an Affine transform.
+type Transformable[T any] interface {
+	// Transform returns a copy of the value moved by a.
+	Transform(a Affine) T
+}
+
+// TransformAll applies a to every element of xs, returning the results
+// in a new slice.
+func TransformAll[T Transformable[T]](xs []T, a Affine) []T {
+	out := make([]T, len(xs))
+	for i, x := range xs {
+		out[i] = x.Transform(a)
+	}
+	return out
+}
+
+// Transform returns p moved by a.
+func (p Point) Transform(a Affine) Point {
+	return a.Apply(p)
+}
+
+// Transform returns v moved by a's linear part, ignoring translation.
+func (v Vector) Transform(a Affine) Vector {
+	return a.ApplyVector(v)
+}
+
+// Transform returns s with both endpoints moved by a.
+func (s Segment) Transform(a Affine) Segment {
+	return Segment{a.Apply(s.Start), a.Apply(s.End)}
+}
+
+// Transform returns the AABB of a's corners once moved by a. Since a
+// may rotate or shear, the result is the bounding box of the
+// transformed corners, not necessarily congruent to the original.
+func (ab AABB) Transform(a Affine) AABB {
+	corners := [4]Point{
+		a.Apply(ab.Min),
+		a.Apply(Pt(ab.Max.X, ab.Min.Y)),
+		a.Apply(ab.Max),
+		a.Apply(Pt(ab.Min.X, ab.Max.Y)),
+	}
+	result := AABB{corners[0], corners[0]}
+	for _, c := range corners[1:] {
+		result.Min.X = min(result.Min.X, c.X)
+		result.Min.Y = min(result.Min.Y, c.Y)
+		result.Max.X = max(result.Max.X, c.X)
+		result.Max.Y = max(result.Max.Y, c.Y)
+	}
+	return result
+}
+
+// Transform returns qb with its control points moved by a. This is
+// exact, since affine maps send Bezier curves to Bezier curves with
+// transformed control points.
+func (qb QuadraticBezier) Transform(a Affine) QuadraticBezier {
+	return Bezier2(a.Apply(qb.a), a.Apply(qb.b), a.Apply(qb.c))
+}
+
+// Transform returns cb with its control points moved by a. This is
+// exact, since affine maps send Bezier curves to Bezier curves with
+// transformed control points.
+func (cb CubicBezier) Transform(a Affine) CubicBezier {
+	return Bezier3(a.Apply(cb.a), a.Apply(cb.b), a.Apply(cb.c), a.Apply(cb.d))
+}
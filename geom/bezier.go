@@ -4,6 +4,19 @@ import (
 	"math"
 )
 
+// defaultFlatnessTol is the default tolerance, in the same units as the
+// curve's control points (typically pixels), used by Points to decide
+// when a subdivided segment of a Bezier curve is flat enough to emit as
+// a straight line.
+const defaultFlatnessTol = 0.5
+
+// maxFlattenDepth caps how many times quadraticFlatten/cubicFlatten will
+// subdivide a segment, regardless of tol. Without it, a non-positive or
+// pathologically small tol (or a zero-length chord, which always has
+// zero flatness) would recurse forever; 16 levels produce up to 2^16
+// segments, far finer than any caller-visible tolerance needs.
+const maxFlattenDepth = 16
+
 type QuadraticBezier struct {
 	a, b, c Point
 }
@@ -17,21 +30,47 @@ func (qb QuadraticBezier) At(t float64) Point {
 	return Point{x, y}
 }
 
+// Points flattens the curve into a polyline using the default flatness
+// tolerance. See PointsTol for details.
 func (qb QuadraticBezier) Points() []Point {
-	l := (math.Hypot(qb.b.X-qb.a.X, qb.b.Y-qb.a.Y) +
-		math.Hypot(qb.c.X-qb.b.X, qb.c.Y-qb.b.Y))
-	n := int(l + 0.5)
-	if n < 4 {
-		n = 4
-	}
-	denom := float64(n) - 1
-	result := make([]Point, n)
-	for i := 0; i < n; i++ {
-		result[i] = qb.At(float64(i) / denom)
+	return qb.PointsTol(defaultFlatnessTol)
+}
+
+// PointsTol flattens the curve into a polyline via adaptive de Casteljau
+// subdivision, recursing until each segment's control polygon deviates
+// from its chord by no more than tol. The first and last points of the
+// result are exactly qb.a and qb.c.
+func (qb QuadraticBezier) PointsTol(tol float64) []Point {
+	var result []Point
+	result = append(result, qb.a)
+	result = quadraticFlatten(qb.a, qb.b, qb.c, tol, maxFlattenDepth, result)
+	return append(result, qb.c)
+}
+
+// quadraticFlatten recursively subdivides the quadratic Bezier curve
+// defined by control points a, b, c, appending interior points (in
+// order, excluding a and c) to result whenever the curve isn't already
+// flat enough. depth is the number of subdivisions remaining; it bottoms
+// out the recursion unconditionally once exhausted, regardless of tol.
+func quadraticFlatten(a, b, c Point, tol float64, depth int, result []Point) []Point {
+	if depth <= 0 || quadraticFlatEnough(a, b, c, tol) {
+		return result
 	}
+	ab := Seg(a, b).At(0.5)
+	bc := Seg(b, c).At(0.5)
+	abc := Seg(ab, bc).At(0.5)
+	result = quadraticFlatten(a, ab, abc, tol, depth-1, result)
+	result = append(result, abc)
+	result = quadraticFlatten(abc, bc, c, tol, depth-1, result)
 	return result
 }
 
+// quadraticFlatEnough reports whether the control point b lies within
+// tol of the chord from a to c.
+func quadraticFlatEnough(a, b, c Point, tol float64) bool {
+	return distanceToChord(a, c, b) <= tol
+}
+
 func quadratic(x0, y0, x1, y1, x2, y2, t float64) (x, y float64) {
 	u := 1 - t
 	a := u * u
@@ -55,22 +94,50 @@ func (cb CubicBezier) At(t float64) Point {
 	return Point{x, y}
 }
 
+// Points flattens the curve into a polyline using the default flatness
+// tolerance. See PointsTol for details.
 func (cb CubicBezier) Points() []Point {
-	l := (math.Hypot(cb.b.X-cb.a.X, cb.b.Y-cb.a.Y) +
-		math.Hypot(cb.c.X-cb.b.X, cb.c.Y-cb.b.Y) +
-		math.Hypot(cb.d.X-cb.c.X, cb.d.Y-cb.c.Y))
-	n := int(l + 0.5)
-	if n < 4 {
-		n = 4
-	}
-	denom := float64(n) - 1
-	result := make([]Point, n)
-	for i := 0; i < n; i++ {
-		result[i] = cb.At(float64(i) / denom)
+	return cb.PointsTol(defaultFlatnessTol)
+}
+
+// PointsTol flattens the curve into a polyline via adaptive de Casteljau
+// subdivision, recursing until each segment's control polygon deviates
+// from its chord by no more than tol. The first and last points of the
+// result are exactly cb.a and cb.d.
+func (cb CubicBezier) PointsTol(tol float64) []Point {
+	var result []Point
+	result = append(result, cb.a)
+	result = cubicFlatten(cb.a, cb.b, cb.c, cb.d, tol, maxFlattenDepth, result)
+	return append(result, cb.d)
+}
+
+// cubicFlatten recursively subdivides the cubic Bezier curve defined by
+// control points a, b, c, d, appending interior points (in order,
+// excluding a and d) to result whenever the curve isn't already flat
+// enough. depth is the number of subdivisions remaining; it bottoms out
+// the recursion unconditionally once exhausted, regardless of tol.
+func cubicFlatten(a, b, c, d Point, tol float64, depth int, result []Point) []Point {
+	if depth <= 0 || cubicFlatEnough(a, b, c, d, tol) {
+		return result
 	}
+	ab := Seg(a, b).At(0.5)
+	bc := Seg(b, c).At(0.5)
+	cd := Seg(c, d).At(0.5)
+	abbc := Seg(ab, bc).At(0.5)
+	bccd := Seg(bc, cd).At(0.5)
+	abcd := Seg(abbc, bccd).At(0.5)
+	result = cubicFlatten(a, ab, abbc, abcd, tol, depth-1, result)
+	result = append(result, abcd)
+	result = cubicFlatten(abcd, bccd, cd, d, tol, depth-1, result)
 	return result
 }
 
+// cubicFlatEnough reports whether the interior control points b and c
+// lie within tol of the chord from a to d.
+func cubicFlatEnough(a, b, c, d Point, tol float64) bool {
+	return distanceToChord(a, d, b) <= tol && distanceToChord(a, d, c) <= tol
+}
+
 func cubic(x0, y0, x1, y1, x2, y2, x3, y3, t float64) (x, y float64) {
 	u := 1 - t
 	a := u * u * u
@@ -81,3 +148,21 @@ func cubic(x0, y0, x1, y1, x2, y2, x3, y3, t float64) (x, y float64) {
 	y = a*y0 + b*y1 + c*y2 + d*y3
 	return
 }
+
+// distanceToChord computes the perpendicular distance from p to the
+// line through chord endpoints a and b. If a and b coincide, it falls
+// back to the distance between p and a.
+func distanceToChord(a, b, p Point) float64 {
+	chord := Vec(a, b)
+	l := chord.Length()
+	if l == 0 {
+		return Vec(a, p).Length()
+	}
+	// The magnitude of the cross product of the chord vector and the
+	// vector from a to p gives the area of the parallelogram they
+	// span; dividing by the chord's length gives the height of that
+	// parallelogram, i.e. the perpendicular distance from p.
+	ap := Vec(a, p)
+	cross := chord.X*ap.Y - chord.Y*ap.X
+	return math.Abs(cross) / l
+}
@@ -0,0 +1,121 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCubicBezierPointsToEndpoints(t *testing.T) {
+	cb := Bezier3(Pt(0, 0), Pt(0, 100), Pt(100, 100), Pt(100, 0))
+	pts := cb.Points()
+	if len(pts) < 2 {
+		t.Fatalf("Points() returned %d points, want at least 2", len(pts))
+	}
+	if pts[0] != cb.a {
+		t.Errorf("first point = %v, want %v", pts[0], cb.a)
+	}
+	if last := pts[len(pts)-1]; last != cb.d {
+		t.Errorf("last point = %v, want %v", last, cb.d)
+	}
+}
+
+func TestCubicBezierStraightLineNeedsNoSubdivision(t *testing.T) {
+	// A "curve" whose control points all lie on the chord from a to d
+	// is already flat, so PointsTol shouldn't subdivide it at all.
+	cb := Bezier3(Pt(0, 0), Pt(33, 33), Pt(66, 66), Pt(100, 100))
+	pts := cb.PointsTol(0.5)
+	if len(pts) != 2 {
+		t.Errorf("PointsTol on a straight line returned %d points, want 2: %v", len(pts), pts)
+	}
+}
+
+func TestCubicBezierTighterTolSubdividesMore(t *testing.T) {
+	cb := Bezier3(Pt(0, 0), Pt(0, 100), Pt(100, 100), Pt(100, 0))
+	loose := cb.PointsTol(5)
+	tight := cb.PointsTol(0.05)
+	if len(tight) <= len(loose) {
+		t.Errorf("tight tolerance produced %d points, loose tolerance produced %d; want tight > loose", len(tight), len(loose))
+	}
+}
+
+func TestCubicBezierZeroTolTerminates(t *testing.T) {
+	// tol <= 0 can never be "flat enough"; quadraticFlatten/cubicFlatten
+	// must still bottom out via maxFlattenDepth instead of recursing
+	// forever.
+	cb := Bezier3(Pt(0, 0), Pt(0, 100), Pt(100, 100), Pt(100, 0))
+	pts := cb.PointsTol(0)
+	if want := 1<<maxFlattenDepth + 1; len(pts) != want {
+		t.Errorf("PointsTol(0) produced %d points, want %d (2^%d + 1, the fully subdivided curve)", len(pts), want, maxFlattenDepth)
+	}
+}
+
+func TestCubicBezierDegenerateChordTerminates(t *testing.T) {
+	// a == d makes the top-level chord zero-length, so distanceToChord
+	// falls back to point distance; this must still terminate (each
+	// subdivision's own sub-chord may or may not be degenerate) rather
+	// than recurse forever, and can never produce more than
+	// maxFlattenDepth levels of subdivision.
+	cb := Bezier3(Pt(0, 0), Pt(50, 50), Pt(-50, 50), Pt(0, 0))
+	pts := cb.PointsTol(0.01)
+	if max := 1<<maxFlattenDepth + 1; len(pts) > max {
+		t.Errorf("PointsTol on a degenerate chord produced %d points, want <= %d (2^%d + 1)", len(pts), max, maxFlattenDepth)
+	}
+}
+
+func TestQuadraticBezierZeroTolTerminates(t *testing.T) {
+	qb := Bezier2(Pt(0, 0), Pt(50, 100), Pt(100, 0))
+	pts := qb.PointsTol(0)
+	if want := 1<<maxFlattenDepth + 1; len(pts) != want {
+		t.Errorf("PointsTol(0) produced %d points, want %d (2^%d + 1, the fully subdivided curve)", len(pts), want, maxFlattenDepth)
+	}
+}
+
+func TestCubicBezierPointsWithinTolOfCurve(t *testing.T) {
+	cb := Bezier3(Pt(0, 0), Pt(0, 100), Pt(100, 100), Pt(100, 0))
+	const tol = 0.5
+	pts := cb.PointsTol(tol)
+	// Sample the true curve far more densely than the flattened
+	// polyline and check every sample is within tol of some segment
+	// of the polyline, as a proxy for the polyline hugging the curve.
+	const samples = 500
+	for i := 0; i <= samples; i++ {
+		want := cb.At(float64(i) / samples)
+		best := math.Inf(1)
+		for j := 0; j < len(pts)-1; j++ {
+			d := distanceToChord(pts[j], pts[j+1], want)
+			if d < best {
+				best = d
+			}
+		}
+		if best > tol+1e-9 {
+			t.Fatalf("curve point %v is %.4f from the flattened polyline, want <= %v", want, best, tol)
+		}
+	}
+}
+
+func TestQuadraticBezierPointsToEndpoints(t *testing.T) {
+	qb := Bezier2(Pt(0, 0), Pt(50, 100), Pt(100, 0))
+	pts := qb.Points()
+	if pts[0] != qb.a {
+		t.Errorf("first point = %v, want %v", pts[0], qb.a)
+	}
+	if last := pts[len(pts)-1]; last != qb.c {
+		t.Errorf("last point = %v, want %v", last, qb.c)
+	}
+}
+
+func TestDistanceToChord(t *testing.T) {
+	tests := []struct {
+		a, b, p Point
+		want    float64
+	}{
+		{Pt(0, 0), Pt(10, 0), Pt(5, 5), 5},
+		{Pt(0, 0), Pt(10, 0), Pt(5, 0), 0},
+		{Pt(0, 0), Pt(0, 0), Pt(3, 4), 5}, // degenerate chord falls back to point distance.
+	}
+	for _, test := range tests {
+		if got := distanceToChord(test.a, test.b, test.p); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("distanceToChord(%v, %v, %v) = %v, want %v", test.a, test.b, test.p, got, test.want)
+		}
+	}
+}
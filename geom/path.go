@@ -0,0 +1,489 @@
+package geom
+
+import "math"
+
+// Path is an ordered sequence of subpaths, each subpath an ordered
+// sequence of line, quadratic Bezier, cubic Bezier, and elliptical arc
+// segments sharing endpoints, mirroring the draw2d/SVG path model.
+//
+// The zero value is an empty Path, ready for use.
+type Path struct {
+	subpaths []subpath
+	cur      Point
+	has      bool // whether the last subpath is still open for appending
+}
+
+type subpath struct {
+	start  Point
+	elems  []elem
+	closed bool
+}
+
+type elemKind int
+
+const (
+	elemLine elemKind = iota
+	elemQuad
+	elemCubic
+	elemArc
+)
+
+// elem is a single segment of a subpath, running from the previous
+// elem's end (or the subpath's start) to end. c0 and c1 are control
+// points for elemQuad (c0 only) and elemCubic; arc describes an
+// elemArc.
+type elem struct {
+	kind   elemKind
+	c0, c1 Point
+	end    Point
+	arc    arcParams
+}
+
+// arcParams describes an elliptical arc, in the same terms as
+// graphics.Context.EllipticalArc.
+type arcParams struct {
+	center     Point
+	rx, ry     float64
+	rot        float64
+	start, end float64 // angles, in radians
+}
+
+func (a arcParams) sweep() float64 {
+	return a.end - a.start
+}
+
+// MoveTo starts a new subpath at pt.
+func (p *Path) MoveTo(pt Point) {
+	p.subpaths = append(p.subpaths, subpath{start: pt})
+	p.cur = pt
+	p.has = true
+}
+
+// LineTo appends a straight line from the current point to pt.
+func (p *Path) LineTo(pt Point) {
+	p.ensureOpen()
+	p.appendElem(elem{kind: elemLine, end: pt})
+	p.cur = pt
+}
+
+// QuadTo appends a quadratic Bezier curve from the current point
+// through ctrl to pt.
+func (p *Path) QuadTo(ctrl, pt Point) {
+	p.ensureOpen()
+	p.appendElem(elem{kind: elemQuad, c0: ctrl, end: pt})
+	p.cur = pt
+}
+
+// CubicTo appends a cubic Bezier curve from the current point through
+// ctrl0 and ctrl1 to pt.
+func (p *Path) CubicTo(ctrl0, ctrl1, pt Point) {
+	p.ensureOpen()
+	p.appendElem(elem{kind: elemCubic, c0: ctrl0, c1: ctrl1, end: pt})
+	p.cur = pt
+}
+
+// ArcTo appends an elliptical arc centered at center with radii rx and
+// ry, rotated by rot radians about the x-axis, sweeping from
+// startAngle through sweepAngle radians. If the current point doesn't
+// already sit on the arc's start, a line is implicitly added to it
+// first, matching the draw2d/SVG arc model.
+func (p *Path) ArcTo(center Point, rx, ry, rot, startAngle, sweepAngle float64) {
+	p.ensureOpen()
+	start := ellipsePoint(center, rx, ry, rot, startAngle)
+	if p.cur != start {
+		p.appendElem(elem{kind: elemLine, end: start})
+	}
+	end := ellipsePoint(center, rx, ry, rot, startAngle+sweepAngle)
+	p.appendElem(elem{
+		kind: elemArc,
+		end:  end,
+		arc:  arcParams{center, rx, ry, rot, startAngle, startAngle + sweepAngle},
+	})
+	p.cur = end
+}
+
+// Close closes the current subpath with a straight line back to its
+// start, if needed, and ends it: any further MoveTo/LineTo/etc. starts
+// a new subpath at the close point.
+func (p *Path) Close() {
+	if !p.has || len(p.subpaths) == 0 {
+		return
+	}
+	last := len(p.subpaths) - 1
+	p.subpaths[last].closed = true
+	p.cur = p.subpaths[last].start
+	p.has = false
+}
+
+// Clone returns a deep copy of p, sharing no slices with it, so that
+// appending to either afterward leaves the other untouched.
+func (p Path) Clone() Path {
+	out := Path{subpaths: make([]subpath, len(p.subpaths)), cur: p.cur, has: p.has}
+	for i, sp := range p.subpaths {
+		out.subpaths[i] = subpath{start: sp.start, closed: sp.closed, elems: append([]elem(nil), sp.elems...)}
+	}
+	return out
+}
+
+func (p *Path) ensureOpen() {
+	if !p.has {
+		p.MoveTo(p.cur)
+	}
+}
+
+func (p *Path) appendElem(e elem) {
+	last := len(p.subpaths) - 1
+	p.subpaths[last].elems = append(p.subpaths[last].elems, e)
+}
+
+// Flatten returns p's subpaths flattened into polylines and
+// concatenated into a single slice of points, each within tol of the
+// original curves. For a Path with more than one subpath, prefer
+// Subpaths to keep them distinct.
+func (p Path) Flatten(tol float64) []Point {
+	var pts []Point
+	for _, sp := range p.Subpaths(tol) {
+		pts = append(pts, sp...)
+	}
+	return pts
+}
+
+// Subpaths flattens each of p's subpaths into its own polyline, each
+// within tol of the original curves.
+func (p Path) Subpaths(tol float64) [][]Point {
+	out := make([][]Point, len(p.subpaths))
+	for i, sp := range p.subpaths {
+		out[i] = flattenSubpath(sp, tol)
+	}
+	return out
+}
+
+// Bounds returns an axis-aligned bounding box for p, approximated from
+// a flattened version of every subpath.
+func (p Path) Bounds() AABB {
+	first := true
+	var result AABB
+	for _, sp := range p.subpaths {
+		for _, pt := range flattenSubpath(sp, defaultFlatnessTol) {
+			if first {
+				result = AABB{pt, pt}
+				first = false
+				continue
+			}
+			result.Min.X = min(result.Min.X, pt.X)
+			result.Min.Y = min(result.Min.Y, pt.Y)
+			result.Max.X = max(result.Max.X, pt.X)
+			result.Max.Y = max(result.Max.Y, pt.Y)
+		}
+	}
+	return result
+}
+
+// Length returns the approximate total arc length of p, summed across
+// every subpath from a flattened version of the path.
+func (p Path) Length() float64 {
+	var total float64
+	for _, sp := range p.subpaths {
+		pts := flattenSubpath(sp, defaultFlatnessTol)
+		for i := 1; i < len(pts); i++ {
+			total += Vec(pts[i-1], pts[i]).Length()
+		}
+	}
+	return total
+}
+
+// Dash returns a new Path containing only the "on" stretches of p,
+// walked along its flattened arc length according to pattern, an
+// alternating sequence of on/off lengths, starting offset into the
+// pattern's cycle.
+func (p Path) Dash(pattern []float64, offset float64) Path {
+	if len(pattern) == 0 {
+		return p
+	}
+	var out Path
+	for _, sp := range p.subpaths {
+		dashSubpath(&out, flattenSubpath(sp, defaultFlatnessTol), pattern, offset)
+	}
+	return out
+}
+
+func dashSubpath(out *Path, pts []Point, pattern []float64, offset float64) {
+	if len(pts) < 2 {
+		return
+	}
+	total := 0.0
+	for _, d := range pattern {
+		total += d
+	}
+	if total <= 0 {
+		return
+	}
+
+	pos := math.Mod(offset, total)
+	if pos < 0 {
+		pos += total
+	}
+	idx := 0
+	for pos >= pattern[idx] {
+		pos -= pattern[idx]
+		idx = (idx + 1) % len(pattern)
+	}
+	on := idx%2 == 0
+	drawing := false
+
+	emit := func(p Point) {
+		if !on {
+			return
+		}
+		if !drawing {
+			out.MoveTo(p)
+			drawing = true
+		} else {
+			out.LineTo(p)
+		}
+	}
+	emit(pts[0])
+
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		segLen := Vec(a, b).Length()
+		if segLen == 0 {
+			continue
+		}
+		dir := Vec(a, b).Scale(1 / segLen)
+		travelled := 0.0
+		for travelled < segLen {
+			step := pattern[idx] - pos
+			if segLen-travelled < step {
+				step = segLen - travelled
+			}
+			travelled += step
+			pos += step
+			emit(a.Add(dir.Scale(travelled)))
+			if pos >= pattern[idx]-1e-9 {
+				pos = 0
+				idx = (idx + 1) % len(pattern)
+				on = !on
+				drawing = false
+			}
+		}
+	}
+}
+
+// flattenSubpath flattens a single subpath into a polyline, within tol
+// of its original curves.
+func flattenSubpath(sp subpath, tol float64) []Point {
+	pts := []Point{sp.start}
+	cur := sp.start
+	for _, e := range sp.elems {
+		switch e.kind {
+		case elemLine:
+			pts = append(pts, e.end)
+		case elemQuad:
+			pts = append(pts, Bezier2(cur, e.c0, e.end).PointsTol(tol)[1:]...)
+		case elemCubic:
+			pts = append(pts, Bezier3(cur, e.c0, e.c1, e.end).PointsTol(tol)[1:]...)
+		case elemArc:
+			pts = append(pts, flattenArc(e.arc, tol)...)
+		}
+		cur = e.end
+	}
+	if sp.closed && (len(pts) == 0 || pts[len(pts)-1] != sp.start) {
+		pts = append(pts, sp.start)
+	}
+	return pts
+}
+
+// flattenArc samples an arc into points, excluding its start point
+// (which the caller already has), at a resolution fine enough to keep
+// the chord within tol of the true arc.
+func flattenArc(a arcParams, tol float64) []Point {
+	r := max(a.rx, a.ry)
+	step := math.Pi / 8
+	if r > 0 && tol > 0 && tol < r {
+		if s := 2 * math.Acos(1-tol/r); s > 0 {
+			step = s
+		}
+	}
+	n := int(math.Ceil(math.Abs(a.sweep()) / step))
+	if n < 1 {
+		n = 1
+	}
+	pts := make([]Point, 0, n)
+	for i := 1; i <= n; i++ {
+		angle := a.start + a.sweep()*float64(i)/float64(n)
+		pts = append(pts, ellipsePoint(a.center, a.rx, a.ry, a.rot, angle))
+	}
+	return pts
+}
+
+func ellipsePoint(center Point, rx, ry, rot, angle float64) Point {
+	x := rx * math.Cos(angle)
+	y := ry * math.Sin(angle)
+	if rot != 0 {
+		cos, sin := math.Cos(rot), math.Sin(rot)
+		x, y = x*cos-y*sin, x*sin+y*cos
+	}
+	return center.Add(Pt(x, y).Vector())
+}
+
+// JoinStyle describes how Stroke joins two consecutive segments.
+type JoinStyle int
+
+const (
+	JoinBevel JoinStyle = iota
+	JoinRound
+	JoinMiter
+)
+
+// CapStyle describes how Stroke ends an open subpath.
+type CapStyle int
+
+const (
+	CapButt CapStyle = iota
+	CapRound
+	CapSquare
+)
+
+// miterLimit bounds how far a JoinMiter join may extend past the
+// stroke width before falling back to a bevel, matching the default
+// used by graphics.LineJoinMiter elsewhere in the module.
+const miterLimit = 10
+
+// Stroke expands p into a filled outline Path of the given width,
+// suitable for rasterization by filling it (e.g. via ebiten's vector
+// primitives). Each subpath of p becomes one or two closed subpaths in
+// the result: one ring for a closed subpath, or a single ring
+// incorporating both caps for an open one.
+func (p Path) Stroke(width float64, join JoinStyle, cap CapStyle) Path {
+	var out Path
+	halfW := width / 2
+	for _, sp := range p.subpaths {
+		pts := flattenSubpath(sp, defaultFlatnessTol)
+		if sp.closed && len(pts) > 0 && pts[len(pts)-1] == sp.start {
+			pts = pts[:len(pts)-1]
+		}
+		if len(pts) < 2 {
+			continue
+		}
+		strokeSubpath(&out, pts, sp.closed, halfW, join, cap)
+	}
+	return out
+}
+
+func strokeSubpath(out *Path, pts []Point, closed bool, halfW float64, join JoinStyle, cap CapStyle) {
+	if closed {
+		right := offsetSide(pts, halfW, join, true, 1)
+		left := offsetSide(pts, halfW, join, true, -1)
+		emitClosed(out, right)
+		emitClosed(out, reversed(left))
+		return
+	}
+
+	right := offsetSide(pts, halfW, join, false, 1)
+	left := offsetSide(pts, halfW, join, false, -1)
+
+	n := len(pts)
+	endDir := Vec(pts[n-2], pts[n-1]).Normalize()
+	startDir := Vec(pts[1], pts[0]).Normalize()
+
+	var ring []Point
+	ring = append(ring, right...)
+	ring = append(ring, capPoints(pts[n-1], endDir, halfW, cap)...)
+	ring = append(ring, reversed(left)...)
+	ring = append(ring, capPoints(pts[0], startDir, halfW, cap)...)
+
+	emitClosed(out, ring)
+}
+
+// offsetSide walks pts and returns the polyline offset by halfW along
+// the right-hand normal of travel (or the left, if sign is -1),
+// inserting join geometry at interior vertices according to join.
+func offsetSide(pts []Point, halfW float64, join JoinStyle, closed bool, sign float64) []Point {
+	n := len(pts)
+	segs := n - 1
+	if closed {
+		segs = n
+	}
+	var side []Point
+	for i := range segs {
+		a, b := pts[i], pts[(i+1)%n]
+		normal := Vec(a, b).Normalize().RightNormal().Scale(sign * halfW)
+		side = append(side, a.Add(normal), b.Add(normal))
+
+		if !closed && i+1 >= segs {
+			continue
+		}
+		c := pts[(i+2)%n]
+		normal2 := Vec(b, c).Normalize().RightNormal().Scale(sign * halfW)
+		switch join {
+		case JoinRound:
+			side = append(side, arcBetween(b, b.Add(normal), b.Add(normal2))...)
+		case JoinMiter:
+			l0 := LineFromPoints(a.Add(normal), b.Add(normal))
+			l1 := LineFromPoints(b.Add(normal2), c.Add(normal2))
+			if m, ok := l0.Intercept(l1); ok && Vec(b, m).Length() <= halfW*miterLimit {
+				side = append(side, m)
+			}
+		}
+	}
+	return side
+}
+
+// arcBetween returns points tracing the shorter arc of the circle
+// centered at center from p0 to p1, excluding the endpoints.
+func arcBetween(center, p0, p1 Point) []Point {
+	r := Vec(center, p0).Length()
+	a0 := math.Atan2(p0.Y-center.Y, p0.X-center.X)
+	a1 := math.Atan2(p1.Y-center.Y, p1.X-center.X)
+	da := math.Mod(a1-a0+math.Pi, 2*math.Pi) - math.Pi
+
+	const steps = 8
+	pts := make([]Point, 0, steps-1)
+	for i := 1; i < steps; i++ {
+		a := a0 + da*float64(i)/steps
+		pts = append(pts, center.Add(Pt(r*math.Cos(a), r*math.Sin(a)).Vector()))
+	}
+	return pts
+}
+
+// capPoints returns the points closing off an open subpath's end at p,
+// whose outward tangent direction is dir, from the right side of the
+// stroke to the left.
+func capPoints(p Point, dir Vector, halfW float64, cap CapStyle) []Point {
+	r := dir.RightNormal().Scale(halfW)
+	switch cap {
+	case CapSquare:
+		tip := dir.Scale(halfW)
+		return []Point{p.Add(r).Add(tip), p.Add(r.Scale(-1)).Add(tip)}
+	case CapRound:
+		const steps = 8
+		pts := make([]Point, 0, steps+1)
+		for i := 0; i <= steps; i++ {
+			t := float64(i) / steps
+			pts = append(pts, p.Add(r.Rotate(t*math.Pi)))
+		}
+		return pts
+	default: // CapButt
+		return []Point{p.Add(r), p.Add(r.Scale(-1))}
+	}
+}
+
+func emitClosed(out *Path, ring []Point) {
+	if len(ring) < 3 {
+		return
+	}
+	out.MoveTo(ring[0])
+	for _, p := range ring[1:] {
+		out.LineTo(p)
+	}
+	out.Close()
+}
+
+func reversed(pts []Point) []Point {
+	out := make([]Point, len(pts))
+	for i, p := range pts {
+		out[len(pts)-1-i] = p
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/mknyszek/eep/geom"
+	"github.com/mknyszek/eep/graphics"
+	"github.com/mknyszek/eep/text"
+)
+
+// drawNode draws a single real node's box and label.
+func drawNode(c *graphics.Context, screen *ebiten.Image, opts Options, n layoutNode) {
+	bounds := n.size.AABB(geom.Pt(n.pos.X-n.size.X/2, n.pos.Y-n.size.Y/2))
+
+	c.SetColor(opts.NodeColor)
+	c.Rect(graphics.Fill, bounds)
+	c.SetColor(opts.BorderColor)
+	c.Rect(graphics.Stroke, bounds)
+
+	text.AutoBox(screen, opts.NodeStyle.Apply(n.label).String(), n.pos, geom.Dim(0.5, 0.5))
+}
+
+// drawEdge routes and strokes a single layoutEdge as a cubic-Bezier
+// curve between its endpoints, flattened via Points for stroking, and
+// draws an arrowhead at whichever end corresponds to the original
+// edge's destination.
+func drawEdge(c *graphics.Context, nodes []layoutNode, opts Options, e layoutEdge) {
+	p0, p1 := nodes[e.from].pos, nodes[e.to].pos
+	ctrl0, ctrl1 := edgeControls(p0, p1, opts.Direction)
+	pts := geom.Bezier3(p0, ctrl0, ctrl1, p1).Points()
+
+	c.MoveTo(pts[0])
+	for _, p := range pts[1:] {
+		c.LineTo(p)
+	}
+	c.Stroke()
+
+	switch {
+	case !e.reversed && e.last:
+		drawArrowhead(c, pts[len(pts)-2], pts[len(pts)-1], opts.EdgeWidth)
+	case e.reversed && e.first:
+		drawArrowhead(c, pts[1], pts[0], opts.EdgeWidth)
+	}
+}
+
+// edgeControls picks control points that keep the curve's tangent
+// aligned with the rank axis at both endpoints, which is what gives
+// routed edges their characteristic flowing S-shape.
+func edgeControls(p0, p1 geom.Point, dir Direction) (geom.Point, geom.Point) {
+	if dir == LeftToRight {
+		midX := (p0.X + p1.X) / 2
+		return geom.Pt(midX, p0.Y), geom.Pt(midX, p1.Y)
+	}
+	midY := (p0.Y + p1.Y) / 2
+	return geom.Pt(p0.X, midY), geom.Pt(p1.X, midY)
+}
+
+// drawArrowhead fills a small triangular arrowhead at dst, oriented
+// away from src.
+func drawArrowhead(c *graphics.Context, src, dst geom.Point, lineWidth float64) {
+	const ahMul = 6             // Arrow head length multiplier.
+	const th = math.Pi / 8      // Rotation angle (arrow head width).
+	ahLen := ahMul * lineWidth // Arrow head length.
+	vec := geom.Vec(dst, src).Normalize().Scale(ahLen)
+	ah0 := dst.Add(vec.Rotate(th))
+	ah1 := dst.Add(vec.Rotate(-th))
+
+	c.MoveTo(dst)
+	c.LineTo(ah0)
+	c.LineTo(ah1)
+	c.ClosePath()
+	c.Fill()
+}
@@ -0,0 +1,355 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mknyszek/eep/geom"
+)
+
+// layoutNode is a node placed by layout: either a real, labeled Node or
+// a dummy inserted along an edge that spans more than one rank.
+type layoutNode struct {
+	id    NodeID
+	label string
+	dummy bool
+
+	rank  int
+	order int
+	pos   geom.Point
+	size  geom.Dimensions
+}
+
+// layoutEdge is a single-rank segment of a user-supplied Edge, after
+// breaking it up across any dummy nodes it was routed through. from
+// and to index into the Slide's nodes slice.
+type layoutEdge struct {
+	from, to int
+
+	// first and last mark the first and last segment of the chain
+	// making up a single user-supplied Edge; non-reversed edges draw
+	// their arrowhead at the last segment's to, reversed ones at the
+	// first segment's from (see reversed).
+	first, last bool
+
+	// reversed is true if this edge was flipped during cycle-breaking,
+	// meaning from and to point the opposite way to the user's Edge.
+	reversed bool
+}
+
+// rawEdge is a user-supplied Edge with its NodeIDs resolved to indices
+// into the node slice, tracked through cycle-breaking.
+type rawEdge struct {
+	from, to int
+	reversed bool
+}
+
+// layout runs the four-phase Sugiyama-style layered layout described in
+// graph.go's package doc and returns the placed nodes (including
+// dummies) and the segmented edges between them.
+//
+// It panics if any Edge references a NodeID not present in nodes: that's
+// a caller mistake (e.g. a typo'd NodeID), and silently aliasing it to
+// some arbitrary node would draw a wrong diagram instead of reporting
+// the error.
+func layout(nodes []Node, edges []Edge, opts Options) ([]layoutNode, []layoutEdge) {
+	index := make(map[NodeID]int, len(nodes))
+	ln := make([]layoutNode, len(nodes))
+	for i, n := range nodes {
+		index[n.ID] = i
+		ln[i] = layoutNode{id: n.ID, label: n.Label, size: labelSize(opts, n.Label)}
+	}
+
+	raw := make([]rawEdge, len(edges))
+	for i, e := range edges {
+		from, ok := index[e.From]
+		if !ok {
+			panic(fmt.Sprintf("graph: edge %d references unknown node id %v (From)", i, e.From))
+		}
+		to, ok := index[e.To]
+		if !ok {
+			panic(fmt.Sprintf("graph: edge %d references unknown node id %v (To)", i, e.To))
+		}
+		raw[i] = rawEdge{from, to, false}
+	}
+
+	// Phase 1: break cycles by reversing back-edges found via DFS.
+	reverseBackEdges(len(nodes), raw)
+
+	// Phase 2: assign ranks via longest-path, then insert dummy nodes
+	// on edges that span more than one rank.
+	assignRanks(ln, raw)
+	le := insertDummies(&ln, raw)
+
+	// Phase 3: reduce crossings by reordering nodes within each rank
+	// using the barycenter of their neighbors in the adjacent rank.
+	ranks := groupByRank(ln)
+	reduceCrossings(ln, ranks, le)
+
+	// Phase 4: assign coordinates along the rank and position ranks
+	// along the layout direction.
+	placeNodes(ln, ranks, le, opts)
+
+	return ln, le
+}
+
+// labelSize returns the box size for a node's label, including padding.
+func labelSize(opts Options, label string) geom.Dimensions {
+	dim := opts.NodeStyle.Apply(label).String().Measure(0)
+	return geom.Dim(dim.X+2*opts.NodePadding.X, dim.Y+2*opts.NodePadding.Y)
+}
+
+// reverseBackEdges flips the from/to of every edge that, in a DFS over
+// the graph, reaches a node currently on the DFS stack (i.e. a back
+// edge), which breaks every cycle using a minimal set of reversals: a
+// DFS tree has no back edges pointing forward, only ones pointing to an
+// ancestor.
+func reverseBackEdges(n int, edges []rawEdge) {
+	out := make([][]int, n) // indices into edges, by source node
+	for i, e := range edges {
+		out[e.from] = append(out[e.from], i)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, n)
+
+	var visit func(u int)
+	visit = func(u int) {
+		state[u] = visiting
+		for _, i := range out[u] {
+			v := edges[i].to
+			switch state[v] {
+			case unvisited:
+				visit(v)
+			case visiting:
+				edges[i].from, edges[i].to = edges[i].to, edges[i].from
+				edges[i].reversed = true
+			}
+		}
+		state[u] = done
+	}
+	for u := range n {
+		if state[u] == unvisited {
+			visit(u)
+		}
+	}
+}
+
+// assignRanks assigns every node a rank such that every edge goes from
+// a lower rank to a higher one, via longest-path ranking: each node's
+// rank is one more than the largest rank among its predecessors.
+func assignRanks(nodes []layoutNode, edges []rawEdge) {
+	n := len(nodes)
+	indeg := make([]int, n)
+	out := make([][]int, n)
+	for _, e := range edges {
+		indeg[e.to]++
+		out[e.from] = append(out[e.from], e.to)
+	}
+
+	queue := make([]int, 0, n)
+	for u := range n {
+		if indeg[u] == 0 {
+			queue = append(queue, u)
+		}
+	}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, v := range out[u] {
+			if nodes[v].rank < nodes[u].rank+1 {
+				nodes[v].rank = nodes[u].rank + 1
+			}
+			indeg[v]--
+			if indeg[v] == 0 {
+				queue = append(queue, v)
+			}
+		}
+	}
+}
+
+// insertDummies splits every edge that spans more than one rank into a
+// chain of single-rank segments through newly appended dummy nodes, so
+// that every layoutEdge connects adjacent ranks.
+func insertDummies(nodes *[]layoutNode, edges []rawEdge) []layoutEdge {
+	var segments []layoutEdge
+	for _, e := range edges {
+		from, to := e.from, e.to
+		prev := from
+		first := true
+		for r := (*nodes)[from].rank + 1; r < (*nodes)[to].rank; r++ {
+			*nodes = append(*nodes, layoutNode{dummy: true, rank: r})
+			dummy := len(*nodes) - 1
+			segments = append(segments, layoutEdge{from: prev, to: dummy, first: first, reversed: e.reversed})
+			prev, first = dummy, false
+		}
+		segments = append(segments, layoutEdge{from: prev, to: to, first: first, last: true, reversed: e.reversed})
+	}
+	return segments
+}
+
+// groupByRank returns the indices of nodes in each rank, in increasing
+// rank order.
+func groupByRank(nodes []layoutNode) [][]int {
+	maxRank := 0
+	for _, n := range nodes {
+		maxRank = max(maxRank, n.rank)
+	}
+	ranks := make([][]int, maxRank+1)
+	for i, n := range nodes {
+		ranks[n.rank] = append(ranks[n.rank], i)
+	}
+	return ranks
+}
+
+// reduceCrossings reorders the nodes within each rank over several
+// down/up sweeps, each time sorting a rank by the median sort key
+// (barycenter) of its neighbors' positions in the adjacent rank that
+// was just visited.
+func reduceCrossings(nodes []layoutNode, ranks [][]int, edges []layoutEdge) {
+	down := make([][]int, len(nodes))
+	up := make([][]int, len(nodes))
+	for _, e := range edges {
+		down[e.to] = append(down[e.to], e.from)
+		up[e.from] = append(up[e.from], e.to)
+	}
+
+	setOrder := func(rank []int) {
+		for i, u := range rank {
+			nodes[u].order = i
+		}
+	}
+	for _, rank := range ranks {
+		setOrder(rank)
+	}
+
+	barycenter := func(u int, neighbors [][]int) (float64, bool) {
+		ns := neighbors[u]
+		if len(ns) == 0 {
+			return 0, false
+		}
+		sum := 0.0
+		for _, v := range ns {
+			sum += float64(nodes[v].order)
+		}
+		return sum / float64(len(ns)), true
+	}
+
+	sweep := func(neighbors [][]int) {
+		for _, rank := range ranks {
+			keys := make(map[int]float64, len(rank))
+			for _, u := range rank {
+				if bc, ok := barycenter(u, neighbors); ok {
+					keys[u] = bc
+				} else {
+					keys[u] = float64(nodes[u].order)
+				}
+			}
+			sort.SliceStable(rank, func(i, j int) bool {
+				return keys[rank[i]] < keys[rank[j]]
+			})
+			setOrder(rank)
+		}
+	}
+
+	const sweeps = 4
+	for i := range sweeps {
+		if i%2 == 0 {
+			sweep(down)
+		} else {
+			sweep(up)
+		}
+	}
+}
+
+// placeNodes assigns each node a final position: coordinates along the
+// rank are evenly spaced and then relaxed toward the average position
+// of each node's neighbors (while enforcing NodeSpacing), and ranks are
+// laid out along Options.Direction using RankSpacing.
+func placeNodes(nodes []layoutNode, ranks [][]int, edges []layoutEdge, opts Options) {
+	down := make([][]int, len(nodes))
+	up := make([][]int, len(nodes))
+	for _, e := range edges {
+		down[e.to] = append(down[e.to], e.from)
+		up[e.from] = append(up[e.from], e.to)
+	}
+
+	primary := make([]float64, len(nodes))
+	for _, rank := range ranks {
+		for i, u := range rank {
+			primary[u] = float64(i) * opts.NodeSpacing
+		}
+	}
+
+	const iterations = 8
+	for range iterations {
+		for _, rank := range ranks {
+			for _, u := range rank {
+				neighbors := append(append([]int{}, down[u]...), up[u]...)
+				if len(neighbors) == 0 {
+					continue
+				}
+				sum := 0.0
+				for _, v := range neighbors {
+					sum += primary[v]
+				}
+				primary[u] = sum / float64(len(neighbors))
+			}
+		}
+		for _, rank := range ranks {
+			enforceSpacing(rank, primary, opts.NodeSpacing)
+		}
+	}
+
+	secondary := make([]float64, len(ranks))
+	pos := 0.0
+	for r, rank := range ranks {
+		rankSize := 0.0
+		for _, u := range rank {
+			rankSize = max(rankSize, rankExtent(nodes[u], opts.Direction))
+		}
+		secondary[r] = pos
+		pos += rankSize + opts.RankSpacing
+	}
+
+	for r, rank := range ranks {
+		for _, u := range rank {
+			nodes[u].pos = axisPoint(opts, primary[u], secondary[r])
+		}
+	}
+}
+
+// enforceSpacing walks rank in order and nudges values apart so that
+// consecutive nodes are at least spacing apart, without reordering
+// them.
+func enforceSpacing(rank []int, primary []float64, spacing float64) {
+	for i := 1; i < len(rank); i++ {
+		if floor := primary[rank[i-1]] + spacing; primary[rank[i]] < floor {
+			primary[rank[i]] = floor
+		}
+	}
+}
+
+// rankExtent returns the size of a node along the rank axis (the axis
+// perpendicular to the direction ranks flow in).
+func rankExtent(n layoutNode, dir Direction) float64 {
+	if dir == LeftToRight {
+		return n.size.X
+	}
+	return n.size.Y
+}
+
+// axisPoint maps a (primary, secondary) coordinate pair, where primary
+// runs along the rank and secondary runs across ranks, to a final
+// screen position according to opts.Direction and opts.Bounds.
+func axisPoint(opts Options, primary, secondary float64) geom.Point {
+	origin := opts.Bounds.Min
+	if opts.Direction == LeftToRight {
+		return geom.Pt(origin.X+secondary, origin.Y+primary)
+	}
+	return geom.Pt(origin.X+primary, origin.Y+secondary)
+}
@@ -0,0 +1,158 @@
+package graph
+
+import "testing"
+
+func TestLayoutPanicsOnUnknownNodeID(t *testing.T) {
+	nodes := []Node{{ID: 1, Label: "a"}, {ID: 2, Label: "b"}}
+	tests := []struct {
+		name string
+		edge Edge
+	}{
+		{"unknown From", Edge{From: 99, To: 2}},
+		{"unknown To", Edge{From: 1, To: 99}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("layout did not panic on an edge referencing an unknown node id")
+				}
+			}()
+			layout(nodes, []Edge{test.edge}, Options{})
+		})
+	}
+}
+
+func TestReverseBackEdgesBreaksCycle(t *testing.T) {
+	// A 3-cycle: 0 -> 1 -> 2 -> 0.
+	edges := []rawEdge{{from: 0, to: 1}, {from: 1, to: 2}, {from: 2, to: 0}}
+	reverseBackEdges(3, edges)
+
+	reversed := 0
+	for _, e := range edges {
+		if e.reversed {
+			reversed++
+		}
+	}
+	if reversed != 1 {
+		t.Fatalf("got %d reversed edges, want exactly 1: %+v", reversed, edges)
+	}
+
+	// The resulting edge set must be acyclic: assignRanks terminates
+	// having given every node a finite rank only if there's no cycle
+	// left for its topological sweep to get stuck on.
+	nodes := make([]layoutNode, 3)
+	assignRanks(nodes, edges)
+	for i, n := range nodes {
+		if n.rank < 0 {
+			t.Errorf("node %d has rank %d after breaking cycle, want >= 0", i, n.rank)
+		}
+	}
+}
+
+func TestAssignRanksLongestPath(t *testing.T) {
+	// Diamond: 0 -> 1, 0 -> 2, 1 -> 3, 2 -> 3. Node 3's rank must be
+	// the longest path from a root (2), not the shortest.
+	edges := []rawEdge{
+		{from: 0, to: 1}, {from: 0, to: 2},
+		{from: 1, to: 3}, {from: 2, to: 3},
+	}
+	nodes := make([]layoutNode, 4)
+	assignRanks(nodes, edges)
+
+	want := []int{0, 1, 1, 2}
+	for i, w := range want {
+		if nodes[i].rank != w {
+			t.Errorf("node %d rank = %d, want %d", i, nodes[i].rank, w)
+		}
+	}
+}
+
+func TestInsertDummiesSpansOneRankAtATime(t *testing.T) {
+	// 0 is rank 0, 1 is rank 3: the edge between them should be split
+	// into three segments through two dummy nodes at ranks 1 and 2.
+	nodes := []layoutNode{
+		{rank: 0},
+		{rank: 3},
+	}
+	edges := []rawEdge{{from: 0, to: 1}}
+	segments := insertDummies(&nodes, edges)
+
+	if len(segments) != 3 {
+		t.Fatalf("got %d segments, want 3: %+v", len(segments), segments)
+	}
+	if len(nodes) != 4 {
+		t.Fatalf("got %d nodes after inserting dummies, want 4: %+v", len(nodes), nodes)
+	}
+	for _, idx := range []int{2, 3} {
+		if !nodes[idx].dummy {
+			t.Errorf("node %d is not marked dummy: %+v", idx, nodes[idx])
+		}
+	}
+	if nodes[2].rank != 1 || nodes[3].rank != 2 {
+		t.Errorf("dummy ranks = %d, %d, want 1, 2", nodes[2].rank, nodes[3].rank)
+	}
+	if !segments[0].first || segments[0].last {
+		t.Errorf("first segment = %+v, want first=true last=false", segments[0])
+	}
+	if segments[2].first || !segments[2].last {
+		t.Errorf("last segment = %+v, want first=false last=true", segments[2])
+	}
+}
+
+func TestGroupByRank(t *testing.T) {
+	nodes := []layoutNode{{rank: 1}, {rank: 0}, {rank: 1}, {rank: 2}}
+	ranks := groupByRank(nodes)
+
+	if len(ranks) != 3 {
+		t.Fatalf("got %d ranks, want 3: %+v", len(ranks), ranks)
+	}
+	if got := ranks[0]; len(got) != 1 || got[0] != 1 {
+		t.Errorf("rank 0 = %v, want [1]", got)
+	}
+	if got := ranks[1]; len(got) != 2 {
+		t.Errorf("rank 1 = %v, want 2 nodes", got)
+	}
+	if got := ranks[2]; len(got) != 1 || got[0] != 3 {
+		t.Errorf("rank 2 = %v, want [3]", got)
+	}
+}
+
+func TestReduceCrossingsUnwindsReversedOrder(t *testing.T) {
+	// Rank 0 has two parents A (node 0) and B (node 1); rank 1 has X
+	// (node 2), fed only by B, and Y (node 3), fed only by A. X starts
+	// before Y, which crosses A->Y over B->X; a barycenter sweep
+	// should swap them to match their single parents' order.
+	nodes := []layoutNode{
+		{rank: 0, order: 0}, // A
+		{rank: 0, order: 1}, // B
+		{rank: 1, order: 0}, // X, fed by B
+		{rank: 1, order: 1}, // Y, fed by A
+	}
+	edges := []layoutEdge{
+		{from: 1, to: 2}, // B -> X
+		{from: 0, to: 3}, // A -> Y
+	}
+	ranks := [][]int{{0, 1}, {2, 3}}
+	reduceCrossings(nodes, ranks, edges)
+
+	if ranks[1][0] != 3 || ranks[1][1] != 2 {
+		t.Errorf("rank 1 order = %v, want [3, 2]", ranks[1])
+	}
+}
+
+func TestEnforceSpacingPreservesOrder(t *testing.T) {
+	rank := []int{0, 1, 2}
+	primary := []float64{0, 1, 1.5}
+	enforceSpacing(rank, primary, 10)
+
+	for i := 1; i < len(rank); i++ {
+		if got := primary[rank[i]] - primary[rank[i-1]]; got < 10 {
+			t.Errorf("gap between rank[%d] and rank[%d] = %v, want >= 10", i-1, i, got)
+		}
+	}
+	// Order itself must be untouched; only the values move apart.
+	if rank[0] != 0 || rank[1] != 1 || rank[2] != 2 {
+		t.Errorf("enforceSpacing reordered rank: %v", rank)
+	}
+}
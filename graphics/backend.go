@@ -0,0 +1,101 @@
+package graphics
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/mknyszek/eep/geom"
+)
+
+// Backend is the destination-specific half of a Context: given path
+// coordinates already passed through the current transform, it's
+// responsible for actually producing marks on some output, whether a
+// raster image, an SVG document, or a PDF page.
+//
+// Context implements every high-level helper (Rect, Arrow, Circle,
+// EllipticalArc, ...) purely in terms of a Backend, so they work
+// unchanged no matter which one is in use.
+type Backend interface {
+	// Path construction, mirroring Context's own method set.
+	MoveTo(pt geom.Point)
+	LineTo(pt geom.Point)
+	QuadTo(ctrl, pt geom.Point)
+	CubicTo(ctrl0, ctrl1, pt geom.Point)
+	ClosePath()
+	ClearPath()
+
+	// SnapshotPath and RestorePath save and restore the current path
+	// only, for Context.WithEmpty's benefit. The snapshot's concrete
+	// type is a Backend implementation detail.
+	SnapshotPath() any
+	RestorePath(snapshot any)
+
+	// Fill and Stroke draw the current path using the current style.
+	// If preserve is false, the path is cleared afterward.
+	Fill(preserve bool)
+	Stroke(preserve bool)
+
+	// Styling.
+	SetColor(clr color.Color)
+	// SetFillPattern and SetStrokePattern make Fill and Stroke,
+	// respectively, sample a Pattern instead of the current color. A
+	// nil Pattern reverts to the current color. SetColor clears both.
+	SetFillPattern(p Pattern)
+	SetStrokePattern(p Pattern)
+	SetLineWidth(w float64)
+	LineWidth() float64
+	SetLineCap(cap LineCap)
+	SetLineJoin(j LineJoin)
+	SetFillRule(r FillRule)
+
+	// SetDash sets the line dash pattern used by Stroke, as alternating
+	// on/off lengths, and the offset into pattern at which the dash
+	// starts. A nil or empty pattern draws a solid line. ClearDash is
+	// equivalent to SetDash(nil, 0).
+	SetDash(pattern []float64, offset float64)
+	ClearDash()
+
+	// Transformation. TransformPoint applies the current matrix; the
+	// rest update it.
+	TransformPoint(pt geom.Point) geom.Point
+	Identity()
+	Translate(x, y float64)
+	Scale(x, y float64)
+	Rotate(rad float64)
+	Skew(sx, sy float64)
+
+	// Clip intersects the active clip region with the current path,
+	// narrowing what subsequent Fill, Stroke, and Blit calls can
+	// affect. ResetClip removes any clip region, once again letting
+	// them affect the whole destination. The active clip region is
+	// part of the state Push and Pop save and restore.
+	Clip()
+	ResetClip()
+
+	// Blit draws img into the destination, positioned by the current
+	// transform.
+	Blit(img *ebiten.Image, bounds geom.AABB)
+
+	// Push saves the full drawing state (path, style, transform,
+	// clip) and Pop restores the most recently pushed one.
+	Push()
+	Pop()
+
+	// Bounds returns the extent of the destination, in its own
+	// coordinate space (before any transform is applied).
+	Bounds() geom.AABB
+}
+
+// FillRule selects how a filled path's self-intersections and
+// subpaths combine to determine what's inside it.
+type FillRule int
+
+const (
+	// FillRuleFillAll fills every subpath independently, ignoring
+	// winding.
+	FillRuleFillAll FillRule = iota
+	// FillRuleNonZero fills using the nonzero winding rule.
+	FillRuleNonZero
+	// FillRuleEvenOdd fills using the even-odd winding rule.
+	FillRuleEvenOdd
+)
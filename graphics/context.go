@@ -1,39 +1,28 @@
+// Package graphics provides a backend-agnostic vector graphics
+// context: the same path-building and drawing calls can render to an
+// ebiten.Image on screen, or export to SVG or PDF, by swapping out the
+// Context's Backend.
 package graphics
 
 import (
-	"image"
-	"image/color"
 	"math"
 
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/mknyszek/eep/geom"
 )
 
 // Context is a vector graphics context for drawing vector graphics.
+//
+// Context's own methods (Rect, Arrow, Circle, Oval, EllipticalArc,
+// WithEmpty) are built entirely out of its embedded Backend's methods,
+// so they work unchanged regardless of which Backend is in use.
 type Context struct {
-	ctx
-	dst      *ebiten.Image
-	vertices []ebiten.Vertex
-	indices  []uint16
-	stack    []ctx
+	Backend
 }
 
-type ctx struct {
-	matrix     ebiten.GeoM
-	color      color.Color
-	path       vector.Path
-	strokeOpts vector.StrokeOptions
-	fillOpts   vector.FillOptions
-	opts       vector.DrawPathOptions
-}
-
-// NewContext creates a new Context with the intent to draw to dst.
-func NewContext(dst *ebiten.Image) *Context {
-	c := &Context{dst: dst}
-	c.color = color.Black
-	c.opts.AntiAlias = true
-	return c
+// NewContextFrom creates a Context that draws through the given
+// Backend, e.g. one returned by NewSVGBackend or NewPDFBackend.
+func NewContextFrom(b Backend) *Context {
+	return &Context{Backend: b}
 }
 
 // High-level drawing functions.
@@ -60,9 +49,9 @@ func (c *Context) Arrow(src, dst geom.Point) {
 		c.Stroke()
 
 		// Compute arrow head points.
-		const ahMul = 7                              // Arrow head length multiplier.
-		const th = math.Pi / 8                       // Rotation angle (arrow head width).
-		ahLen := ahMul * float64(c.strokeOpts.Width) // Arrow head length.
+		const ahMul = 7                // Arrow head length multiplier.
+		const th = math.Pi / 8         // Rotation angle (arrow head width).
+		ahLen := ahMul * c.LineWidth() // Arrow head length.
 		vec := geom.Vec(dst, src).Normalize().Scale(ahLen)
 		ah0 := dst.Add(vec.Rotate(th))
 		ah1 := dst.Add(vec.Rotate(-th))
@@ -112,28 +101,12 @@ func (c *Context) EllipticalArc(m Method, center geom.Point, radiusX, radiusY, f
 	})
 }
 
-// Context stack functions.
-
 // WithEmpty temporarily swaps out the context's path with a new empty path, for the duration of f.
 func (c *Context) WithEmpty(f func(c *Context)) {
-	var tmp vector.Path
-	c.path, tmp = tmp, c.path
+	snapshot := c.SnapshotPath()
+	c.ClearPath()
 	f(c)
-	c.path, tmp = tmp, c.path
-}
-
-// Push clones the current context and pushes it onto the internal stack.
-func (c *Context) Push() {
-	old := c.ctx
-	c.stack = append(c.stack, old)
-	c.path = vector.Path{}
-	c.path.AddPath(&old.path, &vector.AddPathOptions{})
-}
-
-// Pop restores the previously pushed context.
-func (c *Context) Pop() {
-	c.ctx = c.stack[len(c.stack)-1]
-	c.stack = c.stack[:len(c.stack)-1]
+	c.RestorePath(snapshot)
 }
 
 // Draw functions.
@@ -150,12 +123,9 @@ const (
 func (c *Context) Draw(m Method, preserve bool) {
 	switch m {
 	case Fill:
-		vector.FillPath(c.dst, &c.path, &c.fillOpts, &c.opts)
+		c.Backend.Fill(preserve)
 	case Stroke:
-		vector.StrokePath(c.dst, &c.path, &c.strokeOpts, &c.opts)
-	}
-	if !preserve {
-		c.ClearPath()
+		c.Backend.Stroke(preserve)
 	}
 }
 
@@ -183,130 +153,57 @@ func (c *Context) FillPreserve() {
 	c.Draw(Fill, true)
 }
 
-// Styling functions.
+// Clip intersects the current clip region with the current path,
+// restricting subsequent Fill, Stroke, and Blit calls to their shared
+// area. It clears the current path; use ClipPreserve to keep it.
+func (c *Context) Clip() {
+	c.Backend.Clip()
+	c.ClearPath()
+}
 
-// SetColor sets the current color.
-func (c *Context) SetColor(clr color.Color) {
-	c.opts.ColorScale.Reset()
-	c.opts.ColorScale.ScaleWithColor(clr)
+// ClipPreserve is like Clip but does not clear the current path.
+func (c *Context) ClipPreserve() {
+	c.Backend.Clip()
 }
 
-// SetLineWidth sets the width of the stroke to draw.
-func (c *Context) SetLineWidth(w float64) {
-	c.strokeOpts.Width = float32(w)
+// ResetClip removes any clip region set by Clip or ClipPreserve,
+// once again letting Fill, Stroke, and Blit affect the whole
+// destination.
+func (c *Context) ResetClip() {
+	c.Backend.ResetClip()
 }
 
-type LineCap vector.LineCap
+// Styling functions.
+
+type LineCap int
 
 const (
-	LineCapButt   = LineCap(vector.LineCapButt)
-	LineCapRound  = LineCap(vector.LineCapRound)
-	LineCapSquare = LineCap(vector.LineCapSquare)
+	LineCapButt LineCap = iota
+	LineCapRound
+	LineCapSquare
 )
 
-// SetLineCap sets style of line cap.
-func (c *Context) SetLineCap(cap LineCap) {
-	c.strokeOpts.LineCap = vector.LineCap(cap)
-}
-
 type LineJoin struct {
-	join  vector.LineJoin
+	join  lineJoinKind
 	param float64
 }
 
+type lineJoinKind int
+
+const (
+	lineJoinMiter lineJoinKind = iota
+	lineJoinBevel
+	lineJoinRound
+)
+
 var (
-	LineJoinMiterDefault = LineJoin{vector.LineJoinMiter, 0}
-	LineJoinBevel        = LineJoin{vector.LineJoinBevel, 0}
-	LineJoinRound        = LineJoin{vector.LineJoinRound, 0}
+	LineJoinMiterDefault = LineJoin{lineJoinMiter, 0}
+	LineJoinBevel        = LineJoin{lineJoinBevel, 0}
+	LineJoinRound        = LineJoin{lineJoinRound, 0}
 )
 
 func LineJoinMiter(limit float64) LineJoin {
-	return LineJoin{vector.LineJoinMiter, limit}
-}
-
-// SetLineJoin sets style of line join.
-func (c *Context) SetLineJoin(j LineJoin) {
-	c.strokeOpts.LineJoin = j.join
-	c.strokeOpts.MiterLimit = float32(j.param)
-}
-
-// Set Ebiten drawing controls.
-
-// SetFillRule sets the fill rule for drawing. The default is FillRuleFillAll.
-func (c *Context) SetFillRule(r vector.FillRule) {
-	c.fillOpts.FillRule = r
-}
-
-// SetBlend sets the blend rule for drawing. The default is regular alpha blending.
-func (c *Context) SetBlend(b ebiten.Blend) {
-	c.opts.Blend = b
-}
-
-// Basic path primitives.
-
-// MoveTo moves the current point in the path to (x, y).
-func (c *Context) MoveTo(pt geom.Point) {
-	pt = c.TransformPoint(pt)
-	c.path.MoveTo(float32(pt.X), float32(pt.Y))
-}
-
-// LineTo appends the current path with a line from the current point to the provided point, and sets
-// (x, y) as the new current point.
-func (c *Context) LineTo(pt geom.Point) {
-	pt = c.TransformPoint(pt)
-	c.path.LineTo(float32(pt.X), float32(pt.Y))
-}
-
-// QuadTo appends the current path with a quadratic Bezier curve starting at the current point through to dst,
-// using ctrl as the control point.
-func (c *Context) QuadTo(ctrl, dst geom.Point) {
-	ctrl = c.TransformPoint(ctrl)
-	dst = c.TransformPoint(dst)
-	c.path.QuadTo(float32(ctrl.X), float32(ctrl.Y), float32(dst.X), float32(dst.Y))
-}
-
-// CubicTo appends the current path with a cubic Bezier curve starting at the current point through to dst,
-// using ctrl0 and ctrl1 as the control points.
-func (c *Context) CubicTo(ctrl0, ctrl1, dst geom.Point) {
-	ctrl0 = c.TransformPoint(ctrl0)
-	ctrl1 = c.TransformPoint(ctrl1)
-	dst = c.TransformPoint(dst)
-	c.path.CubicTo(float32(ctrl0.X), float32(ctrl0.Y), float32(ctrl1.X), float32(ctrl1.Y), float32(dst.X), float32(dst.Y))
-}
-
-// ClosePath closes the current path.
-func (c *Context) ClosePath() {
-	c.path.Close()
-}
-
-// ClearPath clears the current path.
-func (c *Context) ClearPath() {
-	c.path.Reset()
-}
-
-// Transformation matrix primitives.
-
-// TransformPoint applies the context's transformation.
-func (c *Context) TransformPoint(pt geom.Point) geom.Point {
-	x, y := c.matrix.Apply(pt.X, pt.Y)
-	return geom.Pt(x, y)
-}
-
-// Identity resets the current transformation matrix to the identity matrix.
-// This results in no translating, scaling, rotating, or shearing.
-func (c *Context) Identity() {
-	c.matrix.Reset()
-}
-
-// Translate updates the current matrix with a translation.
-func (c *Context) Translate(x, y float64) {
-	c.matrix.Translate(x, y)
-}
-
-// Scale updates the current matrix with a scaling factor.
-// Scaling occurs about the origin.
-func (c *Context) Scale(x, y float64) {
-	c.matrix.Scale(x, y)
+	return LineJoin{lineJoinMiter, limit}
 }
 
 // ScaleAbout updates the current matrix with a scaling factor.
@@ -317,12 +214,6 @@ func (c *Context) ScaleAbout(sx, sy, x, y float64) {
 	c.Translate(-x, -y)
 }
 
-// Rotate updates the current matrix with a anticlockwise rotation.
-// Rotation occurs about the origin. Angle is specified in radians.
-func (c *Context) Rotate(angle float64) {
-	c.matrix.Rotate(angle)
-}
-
 // RotateAbout updates the current matrix with a anticlockwise rotation.
 // Rotation occurs about the specified point. Angle is specified in radians.
 func (c *Context) RotateAbout(angle float64, pt geom.Point) {
@@ -331,12 +222,6 @@ func (c *Context) RotateAbout(angle float64, pt geom.Point) {
 	c.Translate(pt.X, pt.Y)
 }
 
-// Skew updates the current matrix with a shearing angle.
-// Skewing occurs about the origin.
-func (c *Context) Skew(sx, sy float64) {
-	c.matrix.Skew(sx, sy)
-}
-
 // SkewAbout updates the current matrix with a shearing angle.
 // Skewing occurs about the specified point.
 func (c *Context) SkewAbout(sx, sy float64, pt geom.Point) {
@@ -348,18 +233,6 @@ func (c *Context) SkewAbout(sx, sy float64, pt geom.Point) {
 // InvertY flips the Y axis so that Y grows from bottom to top and Y=0 is at
 // the bottom of the image.
 func (c *Context) InvertY() {
-	c.Translate(0, float64(c.dst.Bounds().Dx()))
+	c.Translate(0, c.Bounds().Dx())
 	c.Scale(1, -1)
 }
-
-var (
-	whiteImage = ebiten.NewImage(3, 3)
-
-	// whiteSubImage is an internal sub image of whiteImage.
-	// Use whiteSubImage at DrawTriangles instead of whiteImage in order to avoid bleeding edges.
-	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
-)
-
-func init() {
-	whiteImage.Fill(color.White)
-}
@@ -0,0 +1,31 @@
+package graphics
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/mknyszek/eep/geom"
+)
+
+// dashFlattenTol is the flattening tolerance used when replaying a
+// dashed geom.Path as a vector.Path, in destination pixels.
+const dashFlattenTol = 0.2
+
+// dashedVectorPath applies pattern and offset to record via geom.Path's
+// own Dash, then flattens and replays the result as a vector.Path.
+// vector.Path has no native dash support, and doesn't expose its
+// segments for us to dash after the fact, so record (a backend-agnostic
+// mirror of the path, already in destination space) is kept around
+// just for this.
+func dashedVectorPath(record geom.Path, pattern []float64, offset float64) *vector.Path {
+	dashed := record.Dash(pattern, offset)
+	var out vector.Path
+	for _, sub := range dashed.Subpaths(dashFlattenTol) {
+		if len(sub) == 0 {
+			continue
+		}
+		out.MoveTo(float32(sub[0].X), float32(sub[0].Y))
+		for _, pt := range sub[1:] {
+			out.LineTo(float32(pt.X), float32(pt.Y))
+		}
+	}
+	return &out
+}
@@ -0,0 +1,401 @@
+package graphics
+
+import (
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/mknyszek/eep/geom"
+)
+
+// ebitenBackend is the default Backend, drawing directly to an
+// *ebiten.Image using ebiten/v2/vector's rasterizer.
+type ebitenBackend struct {
+	state
+	dst   *ebiten.Image
+	stack []state
+}
+
+// state is everything ebitenBackend needs to save and restore via
+// Push and Pop.
+type state struct {
+	matrix     ebiten.GeoM
+	color      color.Color
+	path       vector.Path
+	strokeOpts vector.StrokeOptions
+	fillOpts   vector.FillOptions
+	opts       vector.DrawPathOptions
+
+	// record mirrors path in a backend-agnostic form, used only to
+	// redraw it as a dashed vector.Path in Stroke when a dash pattern
+	// is set; see dasher.go. vector.Path doesn't expose its own
+	// segments, so there's no way to derive this after the fact.
+	record geom.Path
+
+	dashPattern []float64
+	dashOffset  float64
+
+	fillPattern   Pattern
+	strokePattern Pattern
+
+	// clipMask is the active clip region, as a full-destination-sized
+	// alpha mask, or nil if there isn't one. See Clip.
+	clipMask *ebiten.Image
+}
+
+// NewContext creates a new Context that draws directly to dst using
+// ebiten/v2/vector.
+func NewContext(dst *ebiten.Image) *Context {
+	b := &ebitenBackend{dst: dst}
+	b.color = color.Black
+	b.opts.AntiAlias = true
+	return &Context{Backend: b}
+}
+
+func (b *ebitenBackend) MoveTo(pt geom.Point) {
+	pt = b.TransformPoint(pt)
+	b.path.MoveTo(float32(pt.X), float32(pt.Y))
+	b.record.MoveTo(pt)
+}
+
+func (b *ebitenBackend) LineTo(pt geom.Point) {
+	pt = b.TransformPoint(pt)
+	b.path.LineTo(float32(pt.X), float32(pt.Y))
+	b.record.LineTo(pt)
+}
+
+func (b *ebitenBackend) QuadTo(ctrl, pt geom.Point) {
+	ctrl = b.TransformPoint(ctrl)
+	pt = b.TransformPoint(pt)
+	b.path.QuadTo(float32(ctrl.X), float32(ctrl.Y), float32(pt.X), float32(pt.Y))
+	b.record.QuadTo(ctrl, pt)
+}
+
+func (b *ebitenBackend) CubicTo(ctrl0, ctrl1, pt geom.Point) {
+	ctrl0 = b.TransformPoint(ctrl0)
+	ctrl1 = b.TransformPoint(ctrl1)
+	pt = b.TransformPoint(pt)
+	b.path.CubicTo(float32(ctrl0.X), float32(ctrl0.Y), float32(ctrl1.X), float32(ctrl1.Y), float32(pt.X), float32(pt.Y))
+	b.record.CubicTo(ctrl0, ctrl1, pt)
+}
+
+func (b *ebitenBackend) ClosePath() {
+	b.path.Close()
+	b.record.Close()
+}
+
+func (b *ebitenBackend) ClearPath() {
+	b.path.Reset()
+	b.record = geom.Path{}
+}
+
+func (b *ebitenBackend) SnapshotPath() any {
+	return [2]any{b.path, b.record}
+}
+
+func (b *ebitenBackend) RestorePath(snapshot any) {
+	s := snapshot.([2]any)
+	b.path = s[0].(vector.Path)
+	b.record = s[1].(geom.Path)
+}
+
+func (b *ebitenBackend) Fill(preserve bool) {
+	b.drawThroughClip(func(dst *ebiten.Image) {
+		if b.fillPattern != nil {
+			vs, is := b.path.AppendVerticesAndIndicesForFilling(nil, nil)
+			b.drawPatternTriangles(dst, b.fillPattern, vs, is, toEbitenFillRule(b.fillOpts.FillRule))
+		} else {
+			vector.FillPath(dst, &b.path, &b.fillOpts, &b.opts)
+		}
+	})
+	if !preserve {
+		b.ClearPath()
+	}
+}
+
+func (b *ebitenBackend) Stroke(preserve bool) {
+	path := &b.path
+	if len(b.dashPattern) > 0 {
+		path = dashedVectorPath(b.record, b.dashPattern, b.dashOffset)
+	}
+	b.drawThroughClip(func(dst *ebiten.Image) {
+		if b.strokePattern != nil {
+			vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, &b.strokeOpts)
+			b.drawPatternTriangles(dst, b.strokePattern, vs, is, ebiten.FillRuleFillAll)
+		} else {
+			vector.StrokePath(dst, path, &b.strokeOpts, &b.opts)
+		}
+	})
+	if !preserve {
+		b.ClearPath()
+	}
+}
+
+// patternTextureSize is the side length, in pixels, of the image a
+// Pattern is baked into before being texture-mapped onto a filled or
+// stroked path. It's independent of the path's own size on screen:
+// sampling the pattern this finely, rather than once per destination
+// pixel, is what makes drawing with a Pattern cheap.
+const patternTextureSize = 64
+
+// drawPatternTriangles fills the triangles described by vs and is
+// (already in destination pixel coordinates, as produced by
+// AppendVerticesAndIndicesForFilling/Stroke) with p, by baking p into
+// a small texture over the triangles' bounding box and assigning each
+// vertex a UV coordinate from the inverse of the current transform.
+func (b *ebitenBackend) drawPatternTriangles(dst *ebiten.Image, p Pattern, vs []ebiten.Vertex, is []uint16, fillRule ebiten.FillRule) {
+	if len(vs) == 0 {
+		return
+	}
+	inv := b.matrix
+	inv.Invert()
+
+	pts := make([]geom.Point, len(vs))
+	bounds := geom.Bound(math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1))
+	for i, v := range vs {
+		x, y := inv.Apply(float64(v.DstX), float64(v.DstY))
+		pts[i] = geom.Pt(x, y)
+		bounds.Min.X, bounds.Max.X = math.Min(bounds.Min.X, x), math.Max(bounds.Max.X, x)
+		bounds.Min.Y, bounds.Max.Y = math.Min(bounds.Min.Y, y), math.Max(bounds.Max.Y, y)
+	}
+
+	tex := ebiten.NewImage(patternTextureSize, patternTextureSize)
+	dx, dy := bounds.Dx(), bounds.Dy()
+	for ty := range patternTextureSize {
+		for tx := range patternTextureSize {
+			u := (float64(tx) + 0.5) / patternTextureSize
+			v := (float64(ty) + 0.5) / patternTextureSize
+			tex.Set(tx, ty, p.ColorAt(geom.Pt(bounds.Min.X+u*dx, bounds.Min.Y+v*dy)))
+		}
+	}
+
+	for i, pt := range pts {
+		u, v := float32(0.5), float32(0.5)
+		if dx != 0 {
+			u = float32((pt.X - bounds.Min.X) / dx)
+		}
+		if dy != 0 {
+			v = float32((pt.Y - bounds.Min.Y) / dy)
+		}
+		vs[i].SrcX = u * patternTextureSize
+		vs[i].SrcY = v * patternTextureSize
+		vs[i].ColorR, vs[i].ColorG, vs[i].ColorB, vs[i].ColorA = 1, 1, 1, 1
+	}
+
+	var opts ebiten.DrawTrianglesOptions
+	opts.AntiAlias = b.opts.AntiAlias
+	opts.FillRule = fillRule
+	dst.DrawTriangles(vs, is, tex, &opts)
+}
+
+func toEbitenFillRule(r vector.FillRule) ebiten.FillRule {
+	switch r {
+	case vector.FillRuleNonZero:
+		return ebiten.FillRuleNonZero
+	case vector.FillRuleEvenOdd:
+		return ebiten.FillRuleEvenOdd
+	default:
+		return ebiten.FillRuleFillAll
+	}
+}
+
+func (b *ebitenBackend) SetDash(pattern []float64, offset float64) {
+	b.dashPattern = pattern
+	b.dashOffset = offset
+}
+
+func (b *ebitenBackend) ClearDash() {
+	b.dashPattern = nil
+	b.dashOffset = 0
+}
+
+func (b *ebitenBackend) SetColor(clr color.Color) {
+	b.color = clr
+	b.fillPattern = nil
+	b.strokePattern = nil
+	b.opts.ColorScale.Reset()
+	b.opts.ColorScale.ScaleWithColor(clr)
+}
+
+func (b *ebitenBackend) SetFillPattern(p Pattern) {
+	b.fillPattern = p
+}
+
+func (b *ebitenBackend) SetStrokePattern(p Pattern) {
+	b.strokePattern = p
+}
+
+func (b *ebitenBackend) SetLineWidth(w float64) {
+	b.strokeOpts.Width = float32(w)
+}
+
+func (b *ebitenBackend) LineWidth() float64 {
+	return float64(b.strokeOpts.Width)
+}
+
+func (b *ebitenBackend) SetLineCap(cap LineCap) {
+	switch cap {
+	case LineCapRound:
+		b.strokeOpts.LineCap = vector.LineCapRound
+	case LineCapSquare:
+		b.strokeOpts.LineCap = vector.LineCapSquare
+	default:
+		b.strokeOpts.LineCap = vector.LineCapButt
+	}
+}
+
+func (b *ebitenBackend) SetLineJoin(j LineJoin) {
+	switch j.join {
+	case lineJoinBevel:
+		b.strokeOpts.LineJoin = vector.LineJoinBevel
+	case lineJoinRound:
+		b.strokeOpts.LineJoin = vector.LineJoinRound
+	default:
+		b.strokeOpts.LineJoin = vector.LineJoinMiter
+	}
+	b.strokeOpts.MiterLimit = float32(j.param)
+}
+
+func (b *ebitenBackend) SetFillRule(r FillRule) {
+	b.fillOpts.FillRule = toVectorFillRule(r)
+}
+
+func toVectorFillRule(r FillRule) vector.FillRule {
+	switch r {
+	case FillRuleNonZero:
+		return vector.FillRuleNonZero
+	case FillRuleEvenOdd:
+		return vector.FillRuleEvenOdd
+	default:
+		return vector.FillRuleNonZero
+	}
+}
+
+func (b *ebitenBackend) TransformPoint(pt geom.Point) geom.Point {
+	x, y := b.matrix.Apply(pt.X, pt.Y)
+	return geom.Pt(x, y)
+}
+
+func (b *ebitenBackend) Identity() {
+	b.matrix.Reset()
+}
+
+func (b *ebitenBackend) Translate(x, y float64) {
+	b.matrix.Translate(x, y)
+}
+
+func (b *ebitenBackend) Scale(x, y float64) {
+	b.matrix.Scale(x, y)
+}
+
+func (b *ebitenBackend) Rotate(rad float64) {
+	b.matrix.Rotate(rad)
+}
+
+func (b *ebitenBackend) Skew(sx, sy float64) {
+	b.matrix.Skew(sx, sy)
+}
+
+// clipShaderSrc multiplies whatever's already drawn to an offscreen
+// buffer by a clip mask's alpha, discarding color outside the clip.
+// Ebiten images are alpha-premultiplied, so scaling every channel by
+// the mask's alpha is enough to both fade and cut out the masked area.
+const clipShaderSrc = `
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	m := imageSrc1At(srcPos)
+	return c * m.a
+}
+`
+
+var clipShader = sync.OnceValue(func() *ebiten.Shader {
+	s, err := ebiten.NewShader([]byte(clipShaderSrc))
+	if err != nil {
+		panic(err)
+	}
+	return s
+})
+
+// drawThroughClip calls draw with the destination to render into: the
+// real one directly if there's no active clip, or an offscreen buffer
+// the size of the destination otherwise, which is then composited
+// onto the destination through the clip mask via clipShader.
+func (b *ebitenBackend) drawThroughClip(draw func(dst *ebiten.Image)) {
+	if b.clipMask == nil {
+		draw(b.dst)
+		return
+	}
+	size := b.dst.Bounds().Size()
+	scratch := ebiten.NewImage(size.X, size.Y)
+	draw(scratch)
+	opts := &ebiten.DrawRectShaderOptions{}
+	opts.Images[0] = scratch
+	opts.Images[1] = b.clipMask
+	b.dst.DrawRectShader(size.X, size.Y, clipShader(), opts)
+}
+
+// Clip intersects the active clip region with the current path's
+// fill, the way Context.Clip and Context.ClipPreserve use it. Since
+// Ebiten has no scissor test for arbitrary paths, the clip is tracked
+// as a full-destination alpha mask: the path is rasterized into a
+// fresh mask, then, if a clip is already active, multiplied into it
+// via clipShader to get their intersection.
+func (b *ebitenBackend) Clip() {
+	size := b.dst.Bounds().Size()
+	mask := ebiten.NewImage(size.X, size.Y)
+	vector.FillPath(mask, &b.path, &vector.FillOptions{FillRule: b.fillOpts.FillRule}, &vector.DrawPathOptions{AntiAlias: true})
+	if b.clipMask == nil {
+		b.clipMask = mask
+		return
+	}
+	narrowed := ebiten.NewImage(size.X, size.Y)
+	opts := &ebiten.DrawRectShaderOptions{}
+	opts.Images[0] = mask
+	opts.Images[1] = b.clipMask
+	narrowed.DrawRectShader(size.X, size.Y, clipShader(), opts)
+	b.clipMask = narrowed
+}
+
+// ResetClip removes the active clip region, if any. Since clipMask is
+// part of state, an earlier, wider (or absent) clip region still
+// comes back on Pop, same as the rest of the drawing state.
+func (b *ebitenBackend) ResetClip() {
+	b.clipMask = nil
+}
+
+// Blit draws img into the destination, positioned and scaled to fill
+// bounds according to the current transform.
+func (b *ebitenBackend) Blit(img *ebiten.Image, bounds geom.AABB) {
+	size := img.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return
+	}
+	b.drawThroughClip(func(dst *ebiten.Image) {
+		var opts ebiten.DrawImageOptions
+		opts.GeoM.Scale(bounds.Dx()/float64(size.X), bounds.Dy()/float64(size.Y))
+		opts.GeoM.Translate(bounds.Min.X, bounds.Min.Y)
+		opts.GeoM.Concat(b.matrix)
+		dst.DrawImage(img, &opts)
+	})
+}
+
+func (b *ebitenBackend) Push() {
+	b.stack = append(b.stack, b.state)
+	old := b.path
+	b.path = vector.Path{}
+	b.path.AddPath(&old, &vector.AddPathOptions{})
+	b.record = b.record.Clone()
+}
+
+func (b *ebitenBackend) Pop() {
+	b.state = b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+}
+
+func (b *ebitenBackend) Bounds() geom.AABB {
+	return geom.ImageAABB(b.dst.Bounds())
+}
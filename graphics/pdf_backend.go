@@ -0,0 +1,420 @@
+package graphics
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/mknyszek/eep/geom"
+)
+
+// PDFBackend renders a Context's drawing calls into a single-page PDF
+// document, using PDF's own path-construction and painting operators
+// directly.
+type PDFBackend struct {
+	pdfState
+	width, height float64
+	content       bytes.Buffer
+	images        []pdfImage
+	stack         []pdfState
+}
+
+type pdfState struct {
+	matrix   geom.Affine
+	color    color.Color
+	width    float64
+	cap      LineCap
+	join     LineJoin
+	fillRule FillRule
+
+	path    string
+	cur     geom.Point
+	started bool
+
+	dash       []float64
+	dashOffset float64
+
+	fillPattern   Pattern
+	strokePattern Pattern
+
+	// clipPaths are the path operators (in the same "m"/"l"/"c"/"h"
+	// form as path) of every active Clip, outermost first. See Clip.
+	clipPaths []string
+}
+
+// pdfImage is a raster image embedded via Blit, recorded for later
+// emission as a PDF image XObject.
+type pdfImage struct {
+	name          string
+	rgb           []byte
+	width, height int
+}
+
+// NewPDFBackend creates a PDFBackend that renders into a single page
+// of the given point size (1/72 inch units, matching PDF's default
+// user space). Pair it with NewContextFrom to get a Context that
+// draws to it.
+func NewPDFBackend(width, height float64) *PDFBackend {
+	b := &PDFBackend{width: width, height: height}
+	b.matrix = geom.IdentityAffine
+	b.color = color.Black
+	b.width = 1
+	return b
+}
+
+// pdfPoint flips y, since PDF's default user space has its origin at
+// the bottom-left of the page, unlike our top-left, y-down convention.
+func (b *PDFBackend) pdfPoint(pt geom.Point) geom.Point {
+	return geom.Pt(pt.X, b.height-pt.Y)
+}
+
+func (b *PDFBackend) MoveTo(pt geom.Point) {
+	pt = b.TransformPoint(pt)
+	p := b.pdfPoint(pt)
+	b.path += fmt.Sprintf("%g %g m\n", p.X, p.Y)
+	b.cur = pt
+	b.started = true
+}
+
+func (b *PDFBackend) LineTo(pt geom.Point) {
+	pt = b.TransformPoint(pt)
+	p := b.pdfPoint(pt)
+	b.path += fmt.Sprintf("%g %g l\n", p.X, p.Y)
+	b.cur = pt
+}
+
+// QuadTo converts the quadratic Bezier to the cubic PDF's path
+// operators natively support, via the standard degree-elevation
+// formula: the cubic's control points are 2/3 of the way from each
+// endpoint to the quadratic's single control point.
+func (b *PDFBackend) QuadTo(ctrl, pt geom.Point) {
+	ctrl = b.TransformPoint(ctrl)
+	pt = b.TransformPoint(pt)
+	c1 := b.cur.Add(geom.Vec(b.cur, ctrl).Scale(2.0 / 3.0))
+	c2 := pt.Add(geom.Vec(pt, ctrl).Scale(2.0 / 3.0))
+	b.emitCubic(c1, c2, pt)
+}
+
+func (b *PDFBackend) CubicTo(ctrl0, ctrl1, pt geom.Point) {
+	ctrl0 = b.TransformPoint(ctrl0)
+	ctrl1 = b.TransformPoint(ctrl1)
+	pt = b.TransformPoint(pt)
+	b.emitCubic(ctrl0, ctrl1, pt)
+}
+
+func (b *PDFBackend) emitCubic(ctrl0, ctrl1, pt geom.Point) {
+	p0, p1, p2 := b.pdfPoint(ctrl0), b.pdfPoint(ctrl1), b.pdfPoint(pt)
+	b.path += fmt.Sprintf("%g %g %g %g %g %g c\n", p0.X, p0.Y, p1.X, p1.Y, p2.X, p2.Y)
+	b.cur = pt
+}
+
+func (b *PDFBackend) ClosePath() {
+	b.path += "h\n"
+}
+
+func (b *PDFBackend) ClearPath() {
+	b.path = ""
+	b.started = false
+}
+
+func (b *PDFBackend) SnapshotPath() any {
+	return struct {
+		path    string
+		cur     geom.Point
+		started bool
+	}{b.path, b.cur, b.started}
+}
+
+func (b *PDFBackend) RestorePath(snapshot any) {
+	s := snapshot.(struct {
+		path    string
+		cur     geom.Point
+		started bool
+	})
+	b.path, b.cur, b.started = s.path, s.cur, s.started
+}
+
+func (b *PDFBackend) Fill(preserve bool) {
+	if b.started {
+		var body strings.Builder
+		fmt.Fprintf(&body, "%s rg\n%s", b.paintColor(b.fillPattern), b.path)
+		if b.fillRule == FillRuleEvenOdd {
+			body.WriteString("f*\n")
+		} else {
+			body.WriteString("f\n")
+		}
+		b.content.WriteString(b.wrapClip(body.String()))
+	}
+	if !preserve {
+		b.ClearPath()
+	}
+}
+
+func (b *PDFBackend) Stroke(preserve bool) {
+	if b.started {
+		var body strings.Builder
+		fmt.Fprintf(&body, "%s RG\n%g w\n%d J\n%s", b.paintColor(b.strokePattern), b.width, pdfLineCap(b.cap), b.path)
+		join, limit := pdfLineJoin(b.join)
+		fmt.Fprintf(&body, "%d j\n%g M\n%s", join, limit, pdfDash(b.dash, b.dashOffset))
+		body.WriteString("S\n")
+		b.content.WriteString(b.wrapClip(body.String()))
+	}
+	_ = preserve // recorded for symmetry with other backends; PDF simply emits "S" without altering the buffered path.
+	if !preserve {
+		b.ClearPath()
+	}
+}
+
+// wrapClip wraps body, the content-stream operators for one paint
+// operation, in a "q"/"W n"/"Q" trio per active Clip path. The PDF
+// backend appends to one flat content stream rather than nesting
+// "q"/"Q" around Context's own Push/Pop the way an interactive PDF
+// viewer's drawing API might, so each paint operation re-establishes
+// its own clip scope from clipPaths and closes it again immediately
+// after.
+func (b *PDFBackend) wrapClip(body string) string {
+	if len(b.clipPaths) == 0 {
+		return body
+	}
+	var buf strings.Builder
+	for _, p := range b.clipPaths {
+		buf.WriteString("q\n")
+		buf.WriteString(p)
+		buf.WriteString("W n\n")
+	}
+	buf.WriteString(body)
+	for range b.clipPaths {
+		buf.WriteString("Q\n")
+	}
+	return buf.String()
+}
+
+// pdfDash renders a dash pattern as the "d" operator that sets PDF's
+// line dash pattern, or "" (leaving the default solid line in effect)
+// if pattern is empty.
+func pdfDash(pattern []float64, offset float64) string {
+	if len(pattern) == 0 {
+		return ""
+	}
+	s := "["
+	for i, l := range pattern {
+		if i > 0 {
+			s += " "
+		}
+		s += fmt.Sprintf("%g", l)
+	}
+	return fmt.Sprintf("%s] %g d\n", s, offset)
+}
+
+// paintColor returns the PDF color operand to use for the current
+// fill or stroke, given that style's Pattern (nil to just use
+// b.color). This backend doesn't yet support PDF's native shading and
+// tiling patterns, so a Pattern is approximated by the flat color it
+// reports at the origin; full pattern support is tracked separately.
+func (b *PDFBackend) paintColor(p Pattern) string {
+	if p == nil {
+		return pdfColor(b.color)
+	}
+	return pdfColor(p.ColorAt(geom.Origin))
+}
+
+func pdfColor(c color.Color) string {
+	r, g, bl, a := c.RGBA()
+	if a == 0 {
+		return "0 0 0"
+	}
+	r, g, bl = r*0xffff/a, g*0xffff/a, bl*0xffff/a
+	return fmt.Sprintf("%g %g %g", float64(r)/0xffff, float64(g)/0xffff, float64(bl)/0xffff)
+}
+
+func pdfLineCap(c LineCap) int {
+	switch c {
+	case LineCapRound:
+		return 1
+	case LineCapSquare:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func pdfLineJoin(j LineJoin) (join int, miterLimit float64) {
+	limit := j.param
+	if limit <= 0 {
+		limit = 10
+	}
+	switch j.join {
+	case lineJoinRound:
+		return 1, limit
+	case lineJoinBevel:
+		return 2, limit
+	default:
+		return 0, limit
+	}
+}
+
+func (b *PDFBackend) SetColor(clr color.Color) {
+	b.color = clr
+	b.fillPattern = nil
+	b.strokePattern = nil
+}
+
+func (b *PDFBackend) SetFillPattern(p Pattern) {
+	b.fillPattern = p
+}
+
+func (b *PDFBackend) SetStrokePattern(p Pattern) {
+	b.strokePattern = p
+}
+
+func (b *PDFBackend) SetLineWidth(w float64) {
+	b.width = w
+}
+
+func (b *PDFBackend) LineWidth() float64 {
+	return b.width
+}
+
+func (b *PDFBackend) SetLineCap(cap LineCap) {
+	b.cap = cap
+}
+
+func (b *PDFBackend) SetLineJoin(j LineJoin) {
+	b.join = j
+}
+
+func (b *PDFBackend) SetFillRule(r FillRule) {
+	b.fillRule = r
+}
+
+func (b *PDFBackend) SetDash(pattern []float64, offset float64) {
+	b.dash = pattern
+	b.dashOffset = offset
+}
+
+func (b *PDFBackend) ClearDash() {
+	b.dash = nil
+	b.dashOffset = 0
+}
+
+func (b *PDFBackend) TransformPoint(pt geom.Point) geom.Point {
+	return b.matrix.Apply(pt)
+}
+
+func (b *PDFBackend) Identity() {
+	b.matrix = geom.IdentityAffine
+}
+
+func (b *PDFBackend) Translate(x, y float64) {
+	b.matrix = b.matrix.Mul(geom.Translation(geom.Vector{X: x, Y: y}))
+}
+
+func (b *PDFBackend) Scale(x, y float64) {
+	b.matrix = b.matrix.Mul(geom.Scale(x, y))
+}
+
+func (b *PDFBackend) Rotate(rad float64) {
+	b.matrix = b.matrix.Mul(geom.Rotation(rad))
+}
+
+func (b *PDFBackend) Skew(sx, sy float64) {
+	b.matrix = b.matrix.Mul(geom.Shear(sx, sy))
+}
+
+// Clip intersects the active clip region with the current path by
+// recording it in clipPaths; see wrapClip for how it's applied.
+func (b *PDFBackend) Clip() {
+	b.clipPaths = append(b.clipPaths, b.path)
+}
+
+// ResetClip removes every active clip region.
+func (b *PDFBackend) ResetClip() {
+	b.clipPaths = nil
+}
+
+// Blit embeds img as a raw RGB image XObject, positioned and scaled to
+// fill bounds by the current transform.
+func (b *PDFBackend) Blit(img *ebiten.Image, bounds geom.AABB) {
+	size := img.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return
+	}
+	rgb := make([]byte, 0, size.X*size.Y*3)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			rgb = append(rgb, byte(r>>8), byte(g>>8), byte(bl>>8))
+		}
+	}
+	name := fmt.Sprintf("Im%d", len(b.images))
+	b.images = append(b.images, pdfImage{name: name, rgb: rgb, width: size.X, height: size.Y})
+
+	min := b.pdfPoint(b.TransformPoint(geom.Pt(bounds.Min.X, bounds.Max.Y)))
+	body := fmt.Sprintf("q\n%g 0 0 %g %g %g cm\n/%s Do\nQ\n", bounds.Dx(), bounds.Dy(), min.X, min.Y, name)
+	b.content.WriteString(b.wrapClip(body))
+}
+
+func (b *PDFBackend) Push() {
+	b.stack = append(b.stack, b.pdfState)
+}
+
+func (b *PDFBackend) Pop() {
+	b.pdfState = b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+}
+
+func (b *PDFBackend) Bounds() geom.AABB {
+	return geom.Bound(0, 0, b.width, b.height)
+}
+
+// Bytes returns the complete single-page PDF document drawn so far.
+func (b *PDFBackend) Bytes() []byte {
+	var buf bytes.Buffer
+	var offsets []int64
+
+	addObj := func(body string) {
+		offsets = append(offsets, int64(buf.Len()))
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	addStreamObj := func(dict string, data []byte) {
+		offsets = append(offsets, int64(buf.Len()))
+		fmt.Fprintf(&buf, "%d 0 obj\n<< %s /Length %d >>\nstream\n", len(offsets), dict, len(data))
+		buf.Write(data)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	addObj("<< /Type /Catalog /Pages 2 0 R >>")         // 1
+	addObj("<< /Type /Pages /Kids [3 0 R] /Count 1 >>") // 2
+	pageImgNum := 4                                     // object 4 is the content stream; images start at 5
+	resources := "/Resources << >>"
+	if len(b.images) > 0 {
+		dict := ""
+		for i, im := range b.images {
+			dict += fmt.Sprintf("/%s %d 0 R ", im.name, pageImgNum+1+i)
+		}
+		resources = fmt.Sprintf("/Resources << /XObject << %s>> >>", dict)
+	}
+	addObj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Contents 4 0 R %s >>",
+		b.width, b.height, resources)) // 3
+	addStreamObj("", b.content.Bytes()) // 4
+
+	for _, im := range b.images {
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8",
+			im.width, im.height)
+		addStreamObj(dict, im.rgb)
+	}
+
+	xrefStart := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(offsets)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefStart)
+
+	return buf.Bytes()
+}
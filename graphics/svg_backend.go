@@ -0,0 +1,384 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/mknyszek/eep/geom"
+)
+
+// SVGBackend renders a Context's drawing calls into an SVG document
+// instead of to an ebiten.Image, producing a resolution-independent
+// export of the same scene.
+type SVGBackend struct {
+	svgState
+	width, height float64
+	elements      []string
+	defs          []string
+	stack         []svgState
+}
+
+type svgState struct {
+	matrix   geom.Affine
+	color    color.Color
+	width    float64
+	cap      LineCap
+	join     LineJoin
+	fillRule FillRule
+	path     string
+	started  bool
+
+	dash       []float64
+	dashOffset float64
+
+	fillPattern   Pattern
+	strokePattern Pattern
+
+	// clipID is the id of the <clipPath> def for the active clip
+	// region, or "" if there isn't one. See Clip.
+	clipID string
+}
+
+// NewSVGBackend creates an SVGBackend that renders into a document of
+// the given pixel size. Pair it with NewContextFrom to get a Context
+// that draws to it.
+func NewSVGBackend(width, height float64) *SVGBackend {
+	b := &SVGBackend{width: width, height: height}
+	b.matrix = geom.IdentityAffine
+	b.color = color.Black
+	b.width = 1
+	return b
+}
+
+// Bytes returns the complete SVG document drawn so far.
+func (b *SVGBackend) Bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g">`+"\n",
+		b.width, b.height, b.width, b.height)
+	if len(b.defs) > 0 {
+		buf.WriteString("<defs>\n")
+		for _, d := range b.defs {
+			buf.WriteString(d)
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("</defs>\n")
+	}
+	for _, e := range b.elements {
+		buf.WriteString(e)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+func (b *SVGBackend) MoveTo(pt geom.Point) {
+	pt = b.TransformPoint(pt)
+	b.path += fmt.Sprintf("M%g,%g ", pt.X, pt.Y)
+	b.started = true
+}
+
+func (b *SVGBackend) LineTo(pt geom.Point) {
+	pt = b.TransformPoint(pt)
+	b.path += fmt.Sprintf("L%g,%g ", pt.X, pt.Y)
+}
+
+func (b *SVGBackend) QuadTo(ctrl, pt geom.Point) {
+	ctrl = b.TransformPoint(ctrl)
+	pt = b.TransformPoint(pt)
+	b.path += fmt.Sprintf("Q%g,%g %g,%g ", ctrl.X, ctrl.Y, pt.X, pt.Y)
+}
+
+func (b *SVGBackend) CubicTo(ctrl0, ctrl1, pt geom.Point) {
+	ctrl0 = b.TransformPoint(ctrl0)
+	ctrl1 = b.TransformPoint(ctrl1)
+	pt = b.TransformPoint(pt)
+	b.path += fmt.Sprintf("C%g,%g %g,%g %g,%g ", ctrl0.X, ctrl0.Y, ctrl1.X, ctrl1.Y, pt.X, pt.Y)
+}
+
+func (b *SVGBackend) ClosePath() {
+	b.path += "Z "
+}
+
+func (b *SVGBackend) ClearPath() {
+	b.path = ""
+	b.started = false
+}
+
+func (b *SVGBackend) SnapshotPath() any {
+	return b.path
+}
+
+func (b *SVGBackend) RestorePath(snapshot any) {
+	b.path = snapshot.(string)
+}
+
+func (b *SVGBackend) Fill(preserve bool) {
+	b.emit("fill")
+	if !preserve {
+		b.ClearPath()
+	}
+}
+
+func (b *SVGBackend) Stroke(preserve bool) {
+	b.emit("stroke")
+	if !preserve {
+		b.ClearPath()
+	}
+}
+
+// emit appends a <path> element drawing the current path, either
+// filled or stroked with the current color and style.
+func (b *SVGBackend) emit(method string) {
+	if !b.started {
+		return
+	}
+	fillColor, strokeColor := "none", "none"
+	if method == "fill" {
+		if b.fillPattern != nil {
+			fillColor = b.svgPaint(b.fillPattern)
+		} else {
+			fillColor = toCSSColor(b.color)
+		}
+	} else {
+		if b.strokePattern != nil {
+			strokeColor = b.svgPaint(b.strokePattern)
+		} else {
+			strokeColor = toCSSColor(b.color)
+		}
+	}
+	attrs := fmt.Sprintf(`d="%s" fill="%s" stroke="%s"`, strings.TrimSpace(b.path), fillColor, strokeColor)
+	if b.clipID != "" {
+		attrs += fmt.Sprintf(` clip-path="url(#%s)"`, b.clipID)
+	}
+	if method == "stroke" {
+		attrs += fmt.Sprintf(` stroke-width="%g" stroke-linecap="%s" stroke-linejoin="%s"`,
+			b.width, svgLineCap(b.cap), svgLineJoin(b.join))
+		if b.join.join == lineJoinMiter && b.join.param > 0 {
+			attrs += fmt.Sprintf(` stroke-miterlimit="%g"`, b.join.param)
+		}
+		if len(b.dash) > 0 {
+			attrs += fmt.Sprintf(` stroke-dasharray="%s" stroke-dashoffset="%g"`, joinLengths(b.dash), b.dashOffset)
+		}
+	} else if b.fillRule == FillRuleEvenOdd {
+		attrs += ` fill-rule="evenodd"`
+	}
+	b.elements = append(b.elements, fmt.Sprintf("<path %s/>", attrs))
+}
+
+// joinLengths renders a dash pattern as the comma-separated list SVG's
+// stroke-dasharray attribute expects.
+func joinLengths(pattern []float64) string {
+	s := ""
+	for i, l := range pattern {
+		if i > 0 {
+			s += ","
+		}
+		s += fmt.Sprintf("%g", l)
+	}
+	return s
+}
+
+func svgLineCap(c LineCap) string {
+	switch c {
+	case LineCapRound:
+		return "round"
+	case LineCapSquare:
+		return "square"
+	default:
+		return "butt"
+	}
+}
+
+func svgLineJoin(j LineJoin) string {
+	switch j.join {
+	case lineJoinBevel:
+		return "bevel"
+	case lineJoinRound:
+		return "round"
+	default:
+		return "miter"
+	}
+}
+
+func toCSSColor(c color.Color) string {
+	r, g, bl, a := c.RGBA()
+	if a == 0 {
+		return "none"
+	}
+	// Un-premultiply and scale down from 16-bit to 8-bit channels.
+	r, g, bl = r*0xffff/a, g*0xffff/a, bl*0xffff/a
+	if a == 0xffff {
+		return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, bl>>8)
+	}
+	return fmt.Sprintf("rgba(%d,%d,%d,%g)", r>>8, g>>8, bl>>8, float64(a)/0xffff)
+}
+
+// svgPaint returns an SVG paint value (a fill or stroke attribute's
+// value) for p, defining it in <defs> first if it's a gradient or
+// image this backend knows how to render natively. A Pattern of some
+// other, unrecognized type is approximated with the flat color it
+// reports at the origin, since SVG has no way to evaluate an arbitrary
+// per-pixel color function.
+func (b *SVGBackend) svgPaint(p Pattern) string {
+	switch p := p.(type) {
+	case *linearGradient:
+		id := fmt.Sprintf("p%d", len(b.defs))
+		p0, p1 := b.TransformPoint(p.p0), b.TransformPoint(p.p1)
+		b.defs = append(b.defs, fmt.Sprintf(
+			`<linearGradient id="%s" gradientUnits="userSpaceOnUse" x1="%g" y1="%g" x2="%g" y2="%g">%s</linearGradient>`,
+			id, p0.X, p0.Y, p1.X, p1.Y, svgStops(p.stops)))
+		return fmt.Sprintf("url(#%s)", id)
+	case *radialGradient:
+		id := fmt.Sprintf("p%d", len(b.defs))
+		c0, c1 := b.TransformPoint(p.c0), b.TransformPoint(p.c1)
+		b.defs = append(b.defs, fmt.Sprintf(
+			`<radialGradient id="%s" gradientUnits="userSpaceOnUse" fx="%g" fy="%g" fr="%g" cx="%g" cy="%g" r="%g">%s</radialGradient>`,
+			id, c0.X, c0.Y, p.r0, c1.X, c1.Y, p.r1, svgStops(p.stops)))
+		return fmt.Sprintf("url(#%s)", id)
+	case *imagePattern:
+		var encoded bytes.Buffer
+		if err := png.Encode(&encoded, p.img); err != nil {
+			return toCSSColor(p.ColorAt(geom.Origin))
+		}
+		id := fmt.Sprintf("p%d", len(b.defs))
+		w, h := p.bounds.Dx(), p.bounds.Dy()
+		data := base64.StdEncoding.EncodeToString(encoded.Bytes())
+		b.defs = append(b.defs, fmt.Sprintf(
+			`<pattern id="%s" patternUnits="userSpaceOnUse" width="%g" height="%g">`+
+				`<image width="%g" height="%g" href="data:image/png;base64,%s"/></pattern>`,
+			id, w, h, w, h, data))
+		return fmt.Sprintf("url(#%s)", id)
+	default:
+		return toCSSColor(p.ColorAt(geom.Origin))
+	}
+}
+
+// svgStops renders a gradient's stops as the <stop> children of an SVG
+// <linearGradient> or <radialGradient> element.
+func svgStops(stops []Stop) string {
+	var s strings.Builder
+	for _, stop := range stops {
+		fmt.Fprintf(&s, `<stop offset="%g" stop-color="%s"/>`, stop.Offset, toCSSColor(stop.Color))
+	}
+	return s.String()
+}
+
+func (b *SVGBackend) SetColor(clr color.Color) {
+	b.color = clr
+	b.fillPattern = nil
+	b.strokePattern = nil
+}
+
+func (b *SVGBackend) SetFillPattern(p Pattern) {
+	b.fillPattern = p
+}
+
+func (b *SVGBackend) SetStrokePattern(p Pattern) {
+	b.strokePattern = p
+}
+
+func (b *SVGBackend) SetLineWidth(w float64) {
+	b.width = w
+}
+
+func (b *SVGBackend) LineWidth() float64 {
+	return b.width
+}
+
+func (b *SVGBackend) SetLineCap(cap LineCap) {
+	b.cap = cap
+}
+
+func (b *SVGBackend) SetLineJoin(j LineJoin) {
+	b.join = j
+}
+
+func (b *SVGBackend) SetFillRule(r FillRule) {
+	b.fillRule = r
+}
+
+func (b *SVGBackend) SetDash(pattern []float64, offset float64) {
+	b.dash = pattern
+	b.dashOffset = offset
+}
+
+func (b *SVGBackend) ClearDash() {
+	b.dash = nil
+	b.dashOffset = 0
+}
+
+func (b *SVGBackend) TransformPoint(pt geom.Point) geom.Point {
+	return b.matrix.Apply(pt)
+}
+
+func (b *SVGBackend) Identity() {
+	b.matrix = geom.IdentityAffine
+}
+
+func (b *SVGBackend) Translate(x, y float64) {
+	b.matrix = b.matrix.Mul(geom.Translation(geom.Vector{X: x, Y: y}))
+}
+
+func (b *SVGBackend) Scale(x, y float64) {
+	b.matrix = b.matrix.Mul(geom.Scale(x, y))
+}
+
+func (b *SVGBackend) Rotate(rad float64) {
+	b.matrix = b.matrix.Mul(geom.Rotation(rad))
+}
+
+func (b *SVGBackend) Skew(sx, sy float64) {
+	b.matrix = b.matrix.Mul(geom.Shear(sx, sy))
+}
+
+// Clip intersects the active clip region with the current path,
+// defining a <clipPath> from it in <defs>. If a clip is already
+// active, the new <clipPath> references it via its own clip-path
+// attribute, which SVG intersects the same way nested clips would be.
+func (b *SVGBackend) Clip() {
+	id := fmt.Sprintf("c%d", len(b.defs))
+	nested := ""
+	if b.clipID != "" {
+		nested = fmt.Sprintf(` clip-path="url(#%s)"`, b.clipID)
+	}
+	b.defs = append(b.defs, fmt.Sprintf(`<clipPath id="%s"%s><path d="%s"/></clipPath>`, id, nested, strings.TrimSpace(b.path)))
+	b.clipID = id
+}
+
+// ResetClip removes the active clip region, if any.
+func (b *SVGBackend) ResetClip() {
+	b.clipID = ""
+}
+
+// Blit embeds img into the document as a base64-encoded PNG <image>
+// element, positioned and scaled to fill bounds by the current
+// transform.
+func (b *SVGBackend) Blit(img *ebiten.Image, bounds geom.AABB) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return
+	}
+	min, max := b.TransformPoint(bounds.Min), b.TransformPoint(bounds.Max)
+	data := base64.StdEncoding.EncodeToString(encoded.Bytes())
+	b.elements = append(b.elements, fmt.Sprintf(
+		`<image x="%g" y="%g" width="%g" height="%g" href="data:image/png;base64,%s"/>`,
+		min.X, min.Y, max.X-min.X, max.Y-min.Y, data))
+}
+
+func (b *SVGBackend) Push() {
+	b.stack = append(b.stack, b.svgState)
+}
+
+func (b *SVGBackend) Pop() {
+	b.svgState = b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+}
+
+func (b *SVGBackend) Bounds() geom.AABB {
+	return geom.Bound(0, 0, b.width, b.height)
+}
@@ -1,6 +1,8 @@
 package eep
 
 import (
+	"time"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
@@ -20,7 +22,7 @@ import (
 // It does not return unless the window has exited or deck's
 // Update returns a non-nil error.
 func Present(width, height int, deck SlideDeck) error {
-	p := &presentation{width, height, deck}
+	p := &presentation{width: width, height: height, deck: deck}
 	ebiten.SetWindowSize(width, height)
 	ebiten.SetWindowTitle("Ebitengine Presentation")
 	return ebiten.RunGame(p)
@@ -29,6 +31,7 @@ func Present(width, height int, deck SlideDeck) error {
 type presentation struct {
 	width, height int
 	deck          SlideDeck
+	lastUpdate    time.Time
 }
 
 func (p *presentation) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
@@ -42,6 +45,17 @@ func (p *presentation) Update() error {
 	case inpututil.IsKeyJustPressed(ebiten.KeyRight) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
 		p.deck.Next()
 	}
+
+	now := time.Now()
+	var dt time.Duration
+	if !p.lastUpdate.IsZero() {
+		dt = now.Sub(p.lastUpdate)
+	}
+	p.lastUpdate = now
+
+	if as, ok := p.deck.(AnimatedSlide); ok {
+		return as.UpdateAnimated(dt)
+	}
 	return p.deck.Update()
 }
 
@@ -86,8 +100,12 @@ const (
 
 // StaticDeck is a SlideDeck that contains a static set of Slides.
 type StaticDeck struct {
-	currSlide int
-	slides    []Slide
+	currSlide  int
+	slides     []Slide
+	makeTrans  func(from, to Slide) Transition
+	trans      Transition
+	transNext  int
+	lastUpdate time.Time
 }
 
 // Append adds a Slide to the deck.
@@ -95,38 +113,107 @@ func (d *StaticDeck) Append(s ...Slide) {
 	d.slides = append(d.slides, s...)
 }
 
+// Transition sets the Transition the deck plays back between slides
+// when switching via Next or Prev. If unset (the default), slides
+// switch over instantaneously.
+func (d *StaticDeck) Transition(make func(from, to Slide) Transition) {
+	d.makeTrans = make
+}
+
 // Next advances the slide deck forward.
 func (d *StaticDeck) Next() DeckStatus {
-	if d.currSlide < len(d.slides)-1 {
-		d.currSlide++
-		return DeckOK
+	if d.trans != nil {
+		return DeckBusy
+	}
+	if d.currSlide >= len(d.slides)-1 {
+		return DeckEnd
 	}
-	return DeckEnd
+	d.switchTo(d.currSlide + 1)
+	return DeckOK
 }
 
 // Prev advances the slide deck backward.
 func (d *StaticDeck) Prev() DeckStatus {
-	if d.currSlide > 0 {
-		d.currSlide--
-		return DeckOK
+	if d.trans != nil {
+		return DeckBusy
 	}
-	return DeckEnd
+	if d.currSlide <= 0 {
+		return DeckEnd
+	}
+	d.switchTo(d.currSlide - 1)
+	return DeckOK
 }
 
-// Update implements Slide by updating the current Slide.
-func (d *StaticDeck) Update() error {
+// switchTo begins a switch to slide i, either instantaneously or, if a
+// Transition is configured, by starting one.
+func (d *StaticDeck) switchTo(i int) {
+	if d.makeTrans == nil {
+		d.currSlide = i
+		return
+	}
+	d.trans = d.makeTrans(d.slides[d.currSlide], d.slides[i])
+	d.transNext = i
+	d.lastUpdate = time.Time{}
+}
+
+// update advances the deck by dt: the active Transition if one is
+// playing back, or else the current Slide, via UpdateAnimated if it
+// implements AnimatedSlide, or plain Update otherwise.
+func (d *StaticDeck) update(dt time.Duration) error {
+	if d.trans != nil {
+		done, err := d.trans.Update(dt)
+		if err != nil {
+			return err
+		}
+		if done {
+			d.currSlide = d.transNext
+			d.trans = nil
+		}
+		return nil
+	}
+	if as, ok := d.slides[d.currSlide].(AnimatedSlide); ok {
+		return as.UpdateAnimated(dt)
+	}
 	return d.slides[d.currSlide].Update()
 }
 
-// Draw implements Slide by drawing the current Slide.
+// Update implements Slide, measuring dt itself from the wall clock. A
+// dt-aware driver, such as a parent ChainDeck or presentation.Update,
+// calls UpdateAnimated instead, passing its own dt directly.
+func (d *StaticDeck) Update() error {
+	now := time.Now()
+	var dt time.Duration
+	if !d.lastUpdate.IsZero() {
+		dt = now.Sub(d.lastUpdate)
+	}
+	d.lastUpdate = now
+	return d.update(dt)
+}
+
+// UpdateAnimated implements AnimatedSlide, advancing the deck by the
+// caller-supplied dt instead of measuring one itself.
+func (d *StaticDeck) UpdateAnimated(dt time.Duration) error {
+	return d.update(dt)
+}
+
+// Draw implements Slide by drawing the current Slide, or the active
+// Transition if one is playing back.
 func (d *StaticDeck) Draw(screen *ebiten.Image) {
+	if d.trans != nil {
+		d.trans.Draw(screen)
+		return
+	}
 	d.slides[d.currSlide].Draw(screen)
 }
 
 // ChainDeck chains together one or more slide decks.
 type ChainDeck struct {
-	decks    []SlideDeck
-	currDeck int
+	decks      []SlideDeck
+	currDeck   int
+	makeTrans  func(from, to Slide) Transition
+	trans      Transition
+	transNext  int
+	lastUpdate time.Time
 }
 
 // Append adds a Slide to the deck.
@@ -134,36 +221,104 @@ func (d *ChainDeck) Append(s ...SlideDeck) {
 	d.decks = append(d.decks, s...)
 }
 
+// Transition sets the Transition the deck plays back when switching
+// between its sub-decks via Next or Prev. It has no effect on
+// transitions within a sub-deck, which are configured on that sub-deck
+// directly. If unset (the default), sub-decks switch over
+// instantaneously.
+func (d *ChainDeck) Transition(make func(from, to Slide) Transition) {
+	d.makeTrans = make
+}
+
 // Next advances the slide deck forward.
 func (d *ChainDeck) Next() DeckStatus {
+	if d.trans != nil {
+		return DeckBusy
+	}
 	if status := d.decks[d.currDeck].Next(); status != DeckEnd {
 		return status
 	}
-	if d.currDeck < len(d.decks)-1 {
-		d.currDeck++
-		return DeckOK
+	if d.currDeck >= len(d.decks)-1 {
+		return DeckEnd
 	}
-	return DeckEnd
+	d.switchTo(d.currDeck + 1)
+	return DeckOK
 }
 
 // Prev advances the slide deck backward.
 func (d *ChainDeck) Prev() DeckStatus {
+	if d.trans != nil {
+		return DeckBusy
+	}
 	if status := d.decks[d.currDeck].Prev(); status != DeckEnd {
 		return status
 	}
-	if d.currDeck > 0 {
-		d.currDeck--
-		return DeckOK
+	if d.currDeck <= 0 {
+		return DeckEnd
 	}
-	return DeckEnd
+	d.switchTo(d.currDeck - 1)
+	return DeckOK
 }
 
-// Update implements Slide by updating the current Slide.
-func (d *ChainDeck) Update() error {
+// switchTo begins a switch to deck i, either instantaneously or, if a
+// Transition is configured, by starting one.
+func (d *ChainDeck) switchTo(i int) {
+	if d.makeTrans == nil {
+		d.currDeck = i
+		return
+	}
+	d.trans = d.makeTrans(d.decks[d.currDeck], d.decks[i])
+	d.transNext = i
+	d.lastUpdate = time.Time{}
+}
+
+// update advances the deck by dt: the active Transition if one is
+// playing back, or else the current sub-deck, via UpdateAnimated if it
+// implements AnimatedSlide (true of StaticDeck and ChainDeck), or
+// plain Update otherwise.
+func (d *ChainDeck) update(dt time.Duration) error {
+	if d.trans != nil {
+		done, err := d.trans.Update(dt)
+		if err != nil {
+			return err
+		}
+		if done {
+			d.currDeck = d.transNext
+			d.trans = nil
+		}
+		return nil
+	}
+	if as, ok := d.decks[d.currDeck].(AnimatedSlide); ok {
+		return as.UpdateAnimated(dt)
+	}
 	return d.decks[d.currDeck].Update()
 }
 
-// Draw implements Slide by drawing the current Slide.
+// Update implements Slide, measuring dt itself from the wall clock. A
+// dt-aware driver, such as a parent ChainDeck or presentation.Update,
+// calls UpdateAnimated instead, passing its own dt directly.
+func (d *ChainDeck) Update() error {
+	now := time.Now()
+	var dt time.Duration
+	if !d.lastUpdate.IsZero() {
+		dt = now.Sub(d.lastUpdate)
+	}
+	d.lastUpdate = now
+	return d.update(dt)
+}
+
+// UpdateAnimated implements AnimatedSlide, advancing the deck by the
+// caller-supplied dt instead of measuring one itself.
+func (d *ChainDeck) UpdateAnimated(dt time.Duration) error {
+	return d.update(dt)
+}
+
+// Draw implements Slide by drawing the current deck, or the active
+// Transition if one is playing back.
 func (d *ChainDeck) Draw(screen *ebiten.Image) {
+	if d.trans != nil {
+		d.trans.Draw(screen)
+		return
+	}
 	d.decks[d.currDeck].Draw(screen)
 }
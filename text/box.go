@@ -28,10 +28,10 @@ func AutoBox(dst *ebiten.Image, txt String, pos geom.Point, anchor geom.Dimensio
 
 func drawString(dst *ebiten.Image, txt String, orig geom.Point, txtDim geom.Dimensions, boxOpts *BoxOptions) {
 	// Advance our way through the positions and draw.
-	m := newBoxDrawMachine(orig, geom.ImageAABB(dst.Bounds()).Dim(), txtDim, txt.direction, boxOpts.Align, boxOpts.VertAlign)
+	m := newBoxDrawMachine(orig, geom.ImageAABB(dst.Bounds()).Dim(), txtDim, txt.direction(), boxOpts.Align, boxOpts.VertAlign)
 	for segments := range txt.lines() {
 		// Figure out the increments for each line.
-		primary, secondary := measureLine(segments, txt.direction, boxOpts.LineSpacing)
+		primary, secondary := measureLine(segments, boxOpts.LineSpacing)
 
 		// Render the line.
 		m.StartLine(primary)
@@ -39,14 +39,18 @@ func drawString(dst *ebiten.Image, txt String, orig geom.Point, txtDim geom.Dime
 			if seg.text == "\n" {
 				continue
 			}
-			face := seg.style.Face.TextFace()
-
-			var opts text.DrawOptions
-			opts.GeoM.Translate(m.X(), m.Y())
-			opts.ColorScale.ScaleWithColor(seg.style.Color)
-			text.Draw(dst, seg.text, seg.style.Face.TextFace(), &opts)
+			adv, ok := seg.face.DrawCached(dst, seg.text, m.X(), m.Y(), seg.style.Color)
+			if !ok {
+				face := seg.face.TextFace()
+				adv = text.Advance(seg.text, face)
+
+				var opts text.DrawOptions
+				opts.GeoM.Translate(m.X(), m.Y())
+				opts.ColorScale.ScaleWithColor(seg.style.Color)
+				text.Draw(dst, seg.text, face, &opts)
+			}
 
-			m.MoveInLine(text.Advance(seg.text, face))
+			m.MoveInLine(adv)
 		}
 
 		// Advance the line.
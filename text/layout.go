@@ -0,0 +1,396 @@
+package text
+
+import (
+	"math"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/mknyszek/eep/font"
+)
+
+// LayoutAlign describes the horizontal alignment of lines produced by
+// LayoutInBox.
+type LayoutAlign int
+
+const (
+	LayoutLeft LayoutAlign = iota
+	LayoutCenter
+	LayoutRight
+	LayoutJustify
+)
+
+// LayoutOptions configures LayoutInBox.
+type LayoutOptions struct {
+	// Align sets the horizontal alignment of each line. LayoutJustify
+	// stretches or shrinks interword spacing so that every line but the
+	// last of each paragraph fills width exactly.
+	Align LayoutAlign
+
+	// Tolerance is the maximum adjustment ratio a line may have and
+	// still be considered feasible. Larger values allow looser lines
+	// before falling back to an overfull one. Zero means 1.0.
+	Tolerance float64
+
+	// Hyphenate, if set, is called with each word that doesn't fit
+	// within a line on its own to find legal hyphenation points within
+	// it, as byte offsets into the word at which a soft hyphen may be
+	// inserted. If nil, words are never broken.
+	Hyphenate func(word string) []int
+}
+
+// LaidOutRun is a single styled glyph run within a LaidOutLine, already
+// positioned along the line.
+type LaidOutRun struct {
+	Text  string
+	Style Style
+	// Face is the font.Face that Text was actually measured with, and
+	// should be rendered with: Style.Face unless Text was rerouted to
+	// one of Style.Fallbacks.
+	Face *font.Face
+	X    float64 // Offset from the left edge of the line.
+}
+
+// LaidOutLine is a single line of text produced by LayoutInBox.
+type LaidOutLine struct {
+	Runs  []LaidOutRun
+	Width float64 // Rendered width of the line, including any justification.
+}
+
+// LayoutInBox breaks s into lines that fit within width, using the
+// Knuth-Plass total-fit algorithm to choose break points. Unlike
+// Measure, which only breaks at explicit newlines, LayoutInBox reflows
+// each paragraph (the text between explicit newlines) to fill width,
+// which is what's needed to justify styled text into a fixed-width
+// column such as a UI panel or dialog box.
+func (s String) LayoutInBox(width float64, opts LayoutOptions) []LaidOutLine {
+	tol := opts.Tolerance
+	if tol <= 0 {
+		tol = 1.0
+	}
+	var out []LaidOutLine
+	for segs := range s.lines() {
+		out = append(out, layoutParagraph(segs, width, opts, tol)...)
+	}
+	return out
+}
+
+// layoutItemKind classifies a layoutItem the way Knuth-Plass classifies
+// the elements of a paragraph: boxes (glyph runs), glue (stretchable
+// interword space), and penalties (optional break points, such as
+// hyphenation points).
+type layoutItemKind int
+
+const (
+	itemBox layoutItemKind = iota
+	itemGlue
+	itemPenalty
+)
+
+type layoutItem struct {
+	kind    layoutItemKind
+	text    string // Box text, or the pre-break text inserted if a penalty is taken.
+	style   Style
+	face    *font.Face // Face actually used to measure text, possibly one of style.Fallbacks.
+	width   float64
+	stretch float64
+	shrink  float64
+	penalty float64
+	flagged bool // Hyphenation point, for flagged-line demerits.
+}
+
+const (
+	hyphenPenalty       = 50
+	doubleHyphenDemerit = 3000
+	linePenalty         = 10
+)
+
+// layoutParagraph lays out a single paragraph (a run of segments ending
+// in, but not including, its trailing "\n" segment).
+func layoutParagraph(segs []segment, width float64, opts LayoutOptions, tol float64) []LaidOutLine {
+	var items []layoutItem
+	atLineStart := true
+	for _, seg := range segs {
+		if seg.text == "\n" {
+			continue
+		}
+		face := seg.face.TextFace()
+		for _, tok := range splitTokens(seg.text) {
+			if tok.space {
+				if atLineStart {
+					continue
+				}
+				w := text.Advance(" ", face)
+				items = append(items, layoutItem{kind: itemGlue, width: w, stretch: w / 2, shrink: w / 3})
+				continue
+			}
+			atLineStart = false
+			items = appendWordItems(items, tok.text, seg.style, seg.face, face, opts.Hyphenate)
+		}
+	}
+	for len(items) > 0 && items[len(items)-1].kind == itemGlue {
+		items = items[:len(items)-1]
+	}
+	if len(items) == 0 {
+		return []LaidOutLine{{}}
+	}
+	return breakParagraph(items, width, opts.Align, tol)
+}
+
+type token struct {
+	text  string
+	space bool
+}
+
+// splitTokens splits s into runs of whitespace and non-whitespace.
+func splitTokens(s string) []token {
+	var out []token
+	start := 0
+	inSpace := false
+	started := false
+	for i, r := range s {
+		sp := unicode.IsSpace(r)
+		if !started {
+			inSpace, started = sp, true
+		}
+		if sp != inSpace {
+			out = append(out, token{s[start:i], inSpace})
+			start, inSpace = i, sp
+		}
+	}
+	if started {
+		out = append(out, token{s[start:], inSpace})
+	}
+	return out
+}
+
+// appendWordItems appends word to items as a single box, or as
+// multiple boxes separated by discretionary hyphenation penalties if
+// hyphenate finds any legal break points within it. face is the
+// resolved font.Face used to measure word (seg.face), and textFace is
+// its TextFace, already looked up by the caller.
+func appendWordItems(items []layoutItem, word string, style Style, face *font.Face, textFace text.Face, hyphenate func(string) []int) []layoutItem {
+	if hyphenate == nil {
+		return append(items, layoutItem{kind: itemBox, text: word, style: style, face: face, width: text.Advance(word, textFace)})
+	}
+	cuts := hyphenate(word)
+	if len(cuts) == 0 {
+		return append(items, layoutItem{kind: itemBox, text: word, style: style, face: face, width: text.Advance(word, textFace)})
+	}
+	hyphenWidth := text.Advance("-", textFace)
+	prev := 0
+	for _, cut := range cuts {
+		if cut <= prev || cut >= len(word) {
+			continue
+		}
+		piece := word[prev:cut]
+		items = append(items, layoutItem{kind: itemBox, text: piece, style: style, face: face, width: text.Advance(piece, textFace)})
+		items = append(items, layoutItem{kind: itemPenalty, text: "-", style: style, face: face, width: hyphenWidth, penalty: hyphenPenalty, flagged: true})
+		prev = cut
+	}
+	return append(items, layoutItem{kind: itemBox, text: word[prev:], style: style, face: face, width: text.Advance(word[prev:], textFace)})
+}
+
+// breakParagraph finds the set of feasible line breaks in items that
+// minimizes total demerits, via a shortest-path search over candidate
+// break positions (equivalent to Dijkstra on the DAG of breakpoints,
+// but expressed as a forward dynamic program since breakpoints only
+// ever move forward through the item list).
+func breakParagraph(items []layoutItem, width float64, align LayoutAlign, tol float64) []LaidOutLine {
+	n := len(items)
+
+	type node struct {
+		dist    float64
+		prev    int
+		flagged bool
+		valid   bool
+	}
+	nodes := make([]node, n+1)
+	nodes[0] = node{valid: true}
+
+	legal := func(pos int) bool {
+		if pos == n {
+			return true
+		}
+		if items[pos].kind == itemPenalty {
+			return true
+		}
+		return items[pos].kind == itemGlue && pos > 0 && items[pos-1].kind == itemBox
+	}
+
+	starts := []int{0}
+	for pos := 1; pos <= n; pos++ {
+		if !legal(pos) {
+			continue
+		}
+		breakIsPenalty := pos < n && items[pos].kind == itemPenalty
+		var penalty float64
+		var flagged bool
+		if breakIsPenalty {
+			penalty, flagged = items[pos].penalty, items[pos].flagged
+		}
+
+		var best node
+		for _, i := range starts {
+			if !nodes[i].valid {
+				continue
+			}
+			natural, stretch, shrink := measureLineItems(items, i, pos, breakIsPenalty)
+			ratio := adjustmentRatio(natural, stretch, shrink, width)
+			if ratio < -1 || ratio > tol {
+				continue
+			}
+			d := lineDemerits(100*math.Pow(math.Abs(ratio), 3), penalty)
+			if flagged && nodes[i].flagged {
+				d += doubleHyphenDemerit
+			}
+			total := nodes[i].dist + d
+			if !best.valid || total < best.dist {
+				best = node{dist: total, prev: i, flagged: flagged, valid: true}
+			}
+		}
+		if !best.valid {
+			// No break within tolerance fits; fall back to the most
+			// recent reachable breakpoint and allow an overfull or
+			// underfull line rather than failing to lay out at all.
+			from := 0
+			for _, i := range starts {
+				if nodes[i].valid && i > from {
+					from = i
+				}
+			}
+			best = node{dist: nodes[from].dist + 1, prev: from, flagged: flagged, valid: true}
+		}
+		nodes[pos] = best
+		starts = append(starts, pos)
+	}
+
+	var breaks []int
+	for pos := n; pos > 0; pos = nodes[pos].prev {
+		breaks = append(breaks, pos)
+	}
+	// breaks is in reverse order; walk it forward to build lines.
+	var lines []LaidOutLine
+	start := 0
+	for i := len(breaks) - 1; i >= 0; i-- {
+		pos := breaks[i]
+		breakIsPenalty := pos < n && items[pos].kind == itemPenalty
+		lines = append(lines, buildLine(items, start, pos, breakIsPenalty, width, align, pos == n))
+		if pos < n {
+			start = pos + 1
+		} else {
+			start = pos
+		}
+	}
+	return lines
+}
+
+// measureLineItems sums the natural width, stretch and shrink of
+// items[start:end). If breakIsPenalty, the pre-break text of the
+// penalty item at items[end] (e.g. a hyphen) is also counted, since
+// taking that break inserts it into the line.
+func measureLineItems(items []layoutItem, start, end int, breakIsPenalty bool) (natural, stretch, shrink float64) {
+	for k := start; k < end; k++ {
+		switch items[k].kind {
+		case itemBox:
+			natural += items[k].width
+		case itemGlue:
+			natural += items[k].width
+			stretch += items[k].stretch
+			shrink += items[k].shrink
+		}
+	}
+	if breakIsPenalty {
+		natural += items[end].width
+	}
+	return natural, stretch, shrink
+}
+
+// adjustmentRatio computes how much a line of the given natural width,
+// stretch and shrink must be stretched (positive) or shrunk (negative)
+// to fill width exactly.
+func adjustmentRatio(natural, stretch, shrink, width float64) float64 {
+	diff := width - natural
+	switch {
+	case diff == 0:
+		return 0
+	case diff > 0:
+		if stretch <= 0 {
+			return math.Inf(1)
+		}
+		return diff / stretch
+	default:
+		if shrink <= 0 {
+			return math.Inf(-1)
+		}
+		return diff / shrink
+	}
+}
+
+// lineDemerits computes the Knuth-Plass demerits for a line with the
+// given badness, ending at a break with the given penalty.
+func lineDemerits(badness, penalty float64) float64 {
+	d := linePenalty + badness
+	d *= d
+	if penalty > 0 {
+		d += penalty * penalty
+	}
+	return d
+}
+
+// buildLine positions the items in [start, end) (plus the discretionary
+// break text at items[end] if breakIsPenalty) into a LaidOutLine,
+// applying justification stretch to glue if align is LayoutJustify and
+// this isn't the last line of its paragraph, then aligning the whole
+// line within width.
+func buildLine(items []layoutItem, start, end int, breakIsPenalty bool, width float64, align LayoutAlign, isLast bool) LaidOutLine {
+	natural, stretch, shrink := measureLineItems(items, start, end, breakIsPenalty)
+	justify := align == LayoutJustify && !isLast
+	ratio := 0.0
+	if justify {
+		ratio = adjustmentRatio(natural, stretch, shrink, width)
+		if math.IsInf(ratio, 0) {
+			ratio = 0
+		}
+		ratio = max(-1, min(ratio, 4))
+	}
+
+	var runs []LaidOutRun
+	x := 0.0
+	for k := start; k < end; k++ {
+		it := items[k]
+		switch it.kind {
+		case itemBox:
+			runs = append(runs, LaidOutRun{Text: it.text, Style: it.style, Face: it.face, X: x})
+			x += it.width
+		case itemGlue:
+			w := it.width
+			if justify {
+				if ratio >= 0 {
+					w += it.stretch * ratio
+				} else {
+					w += it.shrink * ratio
+				}
+			}
+			x += w
+		}
+	}
+	if breakIsPenalty {
+		it := items[end]
+		runs = append(runs, LaidOutRun{Text: it.text, Style: it.style, Face: it.face, X: x})
+		x += it.width
+	}
+
+	var shift float64
+	switch align {
+	case LayoutCenter:
+		shift = (width - x) / 2
+	case LayoutRight:
+		shift = width - x
+	}
+	if shift != 0 {
+		for i := range runs {
+			runs[i].X += shift
+		}
+	}
+	return LaidOutLine{Runs: runs, Width: x}
+}
@@ -0,0 +1,174 @@
+package text
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSplitTokens(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []token
+	}{
+		{"", nil},
+		{"word", []token{{"word", false}}},
+		{"one two", []token{{"one", false}, {" ", true}, {"two", false}}},
+		{"  lead", []token{{"  ", true}, {"lead", false}}},
+		{"trail  ", []token{{"trail", false}, {"  ", true}}},
+	}
+	for _, test := range tests {
+		got := splitTokens(test.in)
+		if len(got) != len(test.want) {
+			t.Errorf("splitTokens(%q) = %v, want %v", test.in, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("splitTokens(%q)[%d] = %v, want %v", test.in, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestAdjustmentRatio(t *testing.T) {
+	tests := []struct {
+		natural, stretch, shrink, width float64
+		want                            float64
+	}{
+		{100, 10, 10, 100, 0},
+		{90, 10, 10, 100, 1},
+		{110, 10, 10, 100, -1},
+		{90, 0, 10, 100, math.Inf(1)},
+		{110, 10, 0, 100, math.Inf(-1)},
+	}
+	for _, test := range tests {
+		got := adjustmentRatio(test.natural, test.stretch, test.shrink, test.width)
+		if got != test.want {
+			t.Errorf("adjustmentRatio(%v, %v, %v, %v) = %v, want %v", test.natural, test.stretch, test.shrink, test.width, got, test.want)
+		}
+	}
+}
+
+func TestLineDemeritsPrefersLowerBadness(t *testing.T) {
+	loose := lineDemerits(10, 0)
+	tight := lineDemerits(0, 0)
+	if tight >= loose {
+		t.Errorf("demerits for badness 0 = %v, want less than badness 10's %v", tight, loose)
+	}
+}
+
+func TestLineDemeritsAddsPenaltySquared(t *testing.T) {
+	base := lineDemerits(0, 0)
+	withPenalty := lineDemerits(0, hyphenPenalty)
+	if want := base + hyphenPenalty*hyphenPenalty; withPenalty != want {
+		t.Errorf("lineDemerits(0, %v) = %v, want %v", hyphenPenalty, withPenalty, want)
+	}
+}
+
+func TestMeasureLineItems(t *testing.T) {
+	items := []layoutItem{
+		{kind: itemBox, width: 10},
+		{kind: itemGlue, width: 5, stretch: 2, shrink: 1},
+		{kind: itemBox, width: 20},
+	}
+	natural, stretch, shrink := measureLineItems(items, 0, 3, false)
+	if natural != 35 || stretch != 2 || shrink != 1 {
+		t.Errorf("measureLineItems = (%v, %v, %v), want (35, 2, 1)", natural, stretch, shrink)
+	}
+}
+
+func TestMeasureLineItemsIncludesPenaltyWidth(t *testing.T) {
+	items := []layoutItem{
+		{kind: itemBox, width: 10},
+		{kind: itemPenalty, width: 3, penalty: hyphenPenalty},
+	}
+	natural, _, _ := measureLineItems(items, 0, 1, true)
+	if natural != 13 {
+		t.Errorf("measureLineItems with breakIsPenalty = %v, want 13", natural)
+	}
+}
+
+// wordItems builds a run of box/glue items for words separated by
+// single-space glue, the same shape layoutParagraph builds from a real
+// paragraph, except widths are given directly instead of measured by a
+// font.Face.
+func wordItems(widths ...float64) []layoutItem {
+	var items []layoutItem
+	for i, w := range widths {
+		if i > 0 {
+			items = append(items, layoutItem{kind: itemGlue, width: 5, stretch: 2.5, shrink: 1.5})
+		}
+		items = append(items, layoutItem{kind: itemBox, text: "x", width: w})
+	}
+	return items
+}
+
+func TestBreakParagraphFitsOneLine(t *testing.T) {
+	// Natural width is 20+5+20+5+20 = 70, with 5 of total stretch; 75
+	// is within the line's adjustment-ratio tolerance of 1.0.
+	items := wordItems(20, 20, 20)
+	lines := breakParagraph(items, 75, LayoutLeft, 1.0)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %+v", len(lines), lines)
+	}
+	if len(lines[0].Runs) != 3 {
+		t.Errorf("got %d runs on the one line, want 3", len(lines[0].Runs))
+	}
+}
+
+func TestBreakParagraphWrapsWhenTooNarrow(t *testing.T) {
+	items := wordItems(40, 40, 40, 40)
+	lines := breakParagraph(items, 50, LayoutLeft, 1.0)
+	if len(lines) < 2 {
+		t.Fatalf("got %d lines, want at least 2: %+v", len(lines), lines)
+	}
+	var words int
+	for _, line := range lines {
+		words += len(line.Runs)
+	}
+	if words != 4 {
+		t.Errorf("got %d words across all lines, want 4 (none dropped or duplicated)", words)
+	}
+}
+
+func TestBreakParagraphJustifyFillsWidthExceptLastLine(t *testing.T) {
+	// Two words per line has a natural width of 40+5+40 = 85, with 2.5
+	// of stretch; 87 is within tolerance, so the break algorithm should
+	// pair the four words into two lines of two rather than, say, three
+	// lines of 1/2/1.
+	items := wordItems(40, 40, 40, 40)
+	const width = 87
+	lines := breakParagraph(items, width, LayoutJustify, 1.0)
+	if len(lines) < 2 {
+		t.Fatalf("got %d lines, want at least 2", len(lines))
+	}
+	for i, line := range lines[:len(lines)-1] {
+		if math.Abs(line.Width-width) > 1e-9 {
+			t.Errorf("line %d width = %v, want exactly %v (justified)", i, line.Width, width)
+		}
+	}
+	last := lines[len(lines)-1]
+	if math.Abs(last.Width-width) < 1e-9 {
+		t.Errorf("last line width = %v, should not be stretched to fill %v", last.Width, width)
+	}
+}
+
+func TestBuildLineAlignment(t *testing.T) {
+	items := []layoutItem{{kind: itemBox, text: "x", width: 40}}
+	const width = 100
+
+	left := buildLine(items, 0, 1, false, width, LayoutLeft, true)
+	if left.Runs[0].X != 0 {
+		t.Errorf("LayoutLeft run.X = %v, want 0", left.Runs[0].X)
+	}
+
+	center := buildLine(items, 0, 1, false, width, LayoutCenter, true)
+	if want := (width - 40.0) / 2; math.Abs(center.Runs[0].X-want) > 1e-9 {
+		t.Errorf("LayoutCenter run.X = %v, want %v", center.Runs[0].X, want)
+	}
+
+	right := buildLine(items, 0, 1, false, width, LayoutRight, true)
+	if want := width - 40.0; math.Abs(right.Runs[0].X-want) > 1e-9 {
+		t.Errorf("LayoutRight run.X = %v, want %v", right.Runs[0].X, want)
+	}
+}
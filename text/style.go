@@ -8,12 +8,21 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/mknyszek/eep/font"
 	"github.com/mknyszek/eep/geom"
+	"github.com/rivo/uniseg"
 )
 
 // Style describes the style of text.
 type Style struct {
 	Face  *font.Face
 	Color color.Color
+
+	// Fallbacks are tried in order for any grapheme cluster that Face
+	// can't render, such as an emoji or a character from a script Face
+	// doesn't cover. A fallback Face is used exactly like Face: it may
+	// point at a bitmap or color source (e.g. a COLR or CBDT emoji
+	// font) just as well as an outline one, since Source doesn't care
+	// which it's given.
+	Fallbacks []*font.Face
 }
 
 // Basic creates a simple style from the provided font name, a font size,
@@ -54,7 +63,7 @@ func (s String) direction() font.TextDirection {
 	if len(s.segments) == 0 {
 		return 0
 	}
-	return s.segments[0].style.Face.TextFace().Direction
+	return s.segments[0].style.Face.Direction()
 }
 
 // Concat concatenates the two styled Strings and returns the result.
@@ -70,12 +79,15 @@ func (s String) Concat(t String) String {
 	}
 }
 
-// segment is a text segment with no line breaks and a single consistent style.
+// segment is a text segment with no line breaks, a single consistent
+// style, and a single face able to render all of it (either style.Face
+// or one of style.Fallbacks).
 //
 // Distinct from Piece because Piece doesn't enforce the "no line breaks" rule.
 type segment struct {
 	text  string
 	style Style
+	face  *font.Face
 }
 
 // Measure returns the dimensions of the String once rendered.
@@ -102,9 +114,9 @@ func (s String) Measure(lineSpacing float64) geom.Dimensions {
 func measureLine(segments []segment, lineSpacing float64) (priLen, secLen float64) {
 	var primary, secondary float64
 	for _, seg := range segments {
-		secondary = max(secondary, seg.style.Face.LineSize(lineSpacing))
+		secondary = max(secondary, seg.face.LineSize(lineSpacing))
 		if seg.text != "\n" {
-			primary += text.Advance(seg.text, seg.style.Face.TextFace())
+			primary += text.Advance(seg.text, seg.face.TextFace())
 		}
 	}
 	return primary, secondary
@@ -161,10 +173,10 @@ type StringBuilder struct {
 // Append appends the provided piece.
 func (s *StringBuilder) Append(piece Piece) {
 	if !s.nonZero {
-		s.direction = piece.Style.Face.TextFace().Direction
+		s.direction = piece.Style.Face.Direction()
 		s.nonZero = true
 	}
-	if s.direction != piece.Style.Face.TextFace().Direction {
+	if s.direction != piece.Style.Face.Direction() {
 		panic("cannot append different text direction to builder")
 	}
 	s.segments = appendSegmentsFromText(s.segments, piece.Text, piece.Style)
@@ -184,14 +196,67 @@ func (s *StringBuilder) Reset() {
 func appendSegmentsFromText(s []segment, text string, style Style) []segment {
 	for line := range strings.Lines(text) {
 		line, ok := strings.CutSuffix(line, "\n")
-		s = append(s, segment{line, style})
+		s = appendSegmentsFromLine(s, line, style)
 		if ok {
-			s = append(s, segment{"\n", style})
+			s = append(s, segment{text: "\n", style: style, face: style.Face})
 		}
 	}
 	return s
 }
 
+// appendSegmentsFromLine splits line (which must contain no newlines)
+// into runs of consecutive grapheme clusters sharing a single face able
+// to render all of them, so that e.g. an emoji or unsupported-script run
+// embedded in an otherwise-plain sentence is rerouted to the first
+// matching entry in style.Fallbacks instead of falling back to style.Face's
+// tofu.
+func appendSegmentsFromLine(s []segment, line string, style Style) []segment {
+	if len(style.Fallbacks) == 0 || line == "" {
+		return append(s, segment{text: line, style: style, face: style.Face})
+	}
+	start := 0
+	cur := style.Face
+	gr := uniseg.NewGraphemes(line)
+	for gr.Next() {
+		from, _ := gr.Positions()
+		face := faceFor(style, gr.Str())
+		if face != cur && from > start {
+			s = append(s, segment{text: line[start:from], style: style, face: cur})
+			start = from
+		}
+		cur = face
+	}
+	if start < len(line) {
+		s = append(s, segment{text: line[start:], style: style, face: cur})
+	}
+	return s
+}
+
+// faceFor picks the first of style.Face and style.Fallbacks (in that
+// order) able to render every rune of cluster, a single grapheme
+// cluster. If none can, style.Face is returned, same as if there were
+// no fallbacks.
+func faceFor(style Style, cluster string) *font.Face {
+	if canRender(style.Face, cluster) {
+		return style.Face
+	}
+	for _, fb := range style.Fallbacks {
+		if canRender(fb, cluster) {
+			return fb
+		}
+	}
+	return style.Face
+}
+
+func canRender(f *font.Face, cluster string) bool {
+	for _, r := range cluster {
+		if !f.HasGlyph(r) {
+			return false
+		}
+	}
+	return true
+}
+
 type noCopy struct{}
 
 func (noCopy) Lock()   {}
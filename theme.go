@@ -4,8 +4,10 @@ import (
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	ebitentext "github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/mknyszek/2d/ebiten/text"
 	"github.com/mknyszek/2d/geom"
+	"github.com/mknyszek/eep/font"
 )
 
 type Theme struct {
@@ -73,6 +75,46 @@ func ContentSlide(t *Theme, title string, makeContent func(text.Style) text.Stri
 	)
 }
 
+// ContentSlideRuns is like ContentSlide, but draws runs instead of a
+// single text.String. This lets a single line mix, e.g., LTR English,
+// RTL Arabic, and vertical Mongolian, each in its own font.Run, without
+// callers hand-managing multiple Faces and styles.
+//
+// runs must already be in visual order; see font.Shape.
+func ContentSlideRuns(t *Theme, title string, runs []font.Run) Slide {
+	return Overlay(
+		BlankContentSlide(t, title),
+		Static(func(screen *ebiten.Image) {
+			d := geom.ImageDim(screen.Bounds())
+			drawRuns(screen, runs, d.X/16, 4*d.Y/16)
+		}),
+	)
+}
+
+// drawRuns draws runs one after another, starting at (origX, origY)
+// and advancing along each run's own primary axis, exactly as
+// font.Shape positions its glyphs.
+func drawRuns(dst *ebiten.Image, runs []font.Run, origX, origY float64) {
+	x, y := origX, origY
+	for _, run := range runs {
+		face := font.RunFace(run)
+
+		var opts ebitentext.DrawOptions
+		opts.GeoM.Translate(x, y)
+		ebitentext.Draw(dst, run.Text, face, &opts)
+
+		adv := ebitentext.Advance(run.Text, face)
+		switch run.Direction {
+		case font.DirectionLeftToRight:
+			x += adv
+		case font.DirectionRightToLeft:
+			x -= adv
+		default:
+			y += adv
+		}
+	}
+}
+
 func BasicContentSlide(t *Theme, title, content string) Slide {
 	return ContentSlide(t, title, func(style text.Style) text.String {
 		return style.Apply(content).String()